@@ -4,15 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 )
 
-var ErrUnknownFormat = errors.New("unknown JSON data format")
-
 // FailedRequestError represents a non-200 response from the Mojang servers,
 // incl. potential JSON error types and messages.
 type FailedRequestError struct {
@@ -36,15 +33,45 @@ func (err *FailedRequestError) Error() string {
 	}
 }
 
-// FetchJSON GETs JSON from an URL and parses it into a map hierarchy.
-// If a non-200 response is returned, the returned url.Error wraps a
-// FailedRequestError.
-func FetchJSON(ctx context.Context, client *http.Client, endpoint string) (interface{}, error) {
-	// Fetch JSON
-	req, _ := http.NewRequest("GET", endpoint, nil) // Error only occurs if endpoint is bad
-	req = req.WithContext(ctx)
+// Client performs requests against the Mojang servers.
+type Client struct {
+	// HTTPClient is the client used to perform requests.
+	// If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// UserAgent is sent as the User-Agent header of every request.
+	// If empty, the default User-Agent of net/http is sent.
+	UserAgent string
+	// Wait, if non-nil, is called before each request is performed, incl.
+	// retries. retry is the number of times the request has been retried
+	// so far. If Wait returns an error, the request is not performed and the
+	// error returned.
+	Wait func(ctx context.Context, retry int) error
+	// Retry, if non-nil, determines how requests failing with a 429 or 5xx
+	// response are retried. If nil, requests are not retried.
+	Retry *RetryPolicy
+}
 
-	resp, err := client.Do(req)
+// FetchJSON GETs JSON from an URL and returns the JSON document received,
+// which may be parsed using Decode. If a non-200 response is returned, the
+// returned url.Error wraps a FailedRequestError. Failed requests are retried
+// by DefaultRetryPolicy.
+func FetchJSON(ctx context.Context, client *http.Client, endpoint string) (json.RawMessage, error) {
+	return (&Client{HTTPClient: client, Retry: DefaultRetryPolicy}).FetchJSON(ctx, endpoint)
+}
+
+// ExchangeJSON POSTs JSON to an URL and returns the JSON document received in
+// response, which may be parsed using Decode. If a non-200 response is
+// returned, the returned url.Error wraps a FailedRequestError. Failed requests
+// are retried by DefaultRetryPolicy.
+func ExchangeJSON(ctx context.Context, client *http.Client, endpoint string, data interface{}) (json.RawMessage, error) {
+	return (&Client{HTTPClient: client, Retry: DefaultRetryPolicy}).ExchangeJSON(ctx, endpoint, data)
+}
+
+// FetchJSON GETs JSON from an URL and returns the JSON document received,
+// which may be parsed using Decode. If a non-200 response is returned, the
+// returned url.Error wraps a FailedRequestError.
+func (c *Client) FetchJSON(ctx context.Context, endpoint string) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -53,20 +80,17 @@ func FetchJSON(ctx context.Context, client *http.Client, endpoint string) (inter
 	return parseResponse(resp.Body, resp.StatusCode, "Get", endpoint)
 }
 
-// ExchangeJSON POSTs JSON to an URL and parses the response JSON into a map
-// hierarchy. If a non-200 response is returned, the returned url.Error wraps
-// a FailedRequestError.
-func ExchangeJSON(ctx context.Context, client *http.Client, endpoint string, data interface{}) (interface{}, error) {
+// ExchangeJSON POSTs JSON to an URL and returns the JSON document received in
+// response, which may be parsed using Decode. If a non-200 response is
+// returned, the returned url.Error wraps a FailedRequestError.
+func (c *Client) ExchangeJSON(ctx context.Context, endpoint string, data interface{}) (json.RawMessage, error) {
 	buf := bytes.Buffer{}
 	err := json.NewEncoder(&buf).Encode(data)
 	if err != nil {
 		return nil, err
 	}
 
-	req, _ := http.NewRequest("POST", endpoint, &buf) // Error only occurs if endpoint is bad
-	req = req.WithContext(ctx)
-
-	resp, err := client.Do(req)
+	resp, err := c.do(ctx, "POST", endpoint, buf.Bytes())
 	if err != nil {
 		return nil, err
 	}
@@ -75,21 +99,87 @@ func ExchangeJSON(ctx context.Context, client *http.Client, endpoint string, dat
 	return parseResponse(resp.Body, resp.StatusCode, "Post", endpoint)
 }
 
-func parseResponse(r io.ReadCloser, statusCode int, op, endpoint string) (interface{}, error) {
-	var j interface{}
+// Fetch GETs the resource at an URL and returns the response body, which the
+// caller must close. If a non-200 response is returned, the returned url.Error
+// wraps a FailedRequestError.
+func (c *Client) Fetch(ctx context.Context, endpoint string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		resp.Body.Close()
+		return nil, &url.Error{
+			Op:  "Get",
+			URL: endpoint,
+			Err: &FailedRequestError{StatusCode: resp.StatusCode},
+		}
+	}
+
+	return resp.Body, nil
+}
+
+// do performs a request, retrying it as specified by c.Retry.
+func (c *Client) do(ctx context.Context, method, endpoint string, body []byte) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := c.attempt(ctx, retry, method, endpoint, body)
+		if err != nil {
+			return nil, err
+		}
+		again, err := c.retry(ctx, retry, resp)
+		if err != nil {
+			return nil, err
+		}
+		if !again {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, retry int, method, endpoint string, body []byte) (*http.Response, error) {
+	if c.Wait != nil {
+		if err := c.Wait(ctx, retry); err != nil {
+			return nil, err
+		}
+	}
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, endpoint, r)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+func parseResponse(r io.ReadCloser, statusCode int, op, endpoint string) (json.RawMessage, error) {
+	var j json.RawMessage
 	parseErr := json.NewDecoder(r).Decode(&j)
 
 	if statusCode != 200 {
 		err := &FailedRequestError{
 			StatusCode: statusCode,
 		}
-		if j, ok := j.(map[string]interface{}); ok && parseErr == nil {
-			if e, ok := j["error"]; ok {
-				err.ErrorCode, _ = e.(string)
-			}
-			if em, ok := j["errorMessage"]; ok {
-				err.ErrorMessage, _ = em.(string)
+		if parseErr == nil {
+			var e struct {
+				Error        string `json:"error"`
+				ErrorMessage string `json:"errorMessage"`
 			}
+			json.Unmarshal(j, &e) // Values of unexpected types are left empty
+			err.ErrorCode, err.ErrorMessage = e.Error, e.ErrorMessage
 		}
 		return nil, &url.Error{
 			Op:  op,
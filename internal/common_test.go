@@ -112,7 +112,7 @@ var testParseResponseInput = [...]struct {
 	op         string
 	endpoint   string
 
-	expRes interface{}
+	expRes json.RawMessage
 	expErr error
 }{
 	{
@@ -120,7 +120,7 @@ var testParseResponseInput = [...]struct {
 		statusCode: 200,
 		op:         "Dummy",
 		endpoint:   "dummyURL",
-		expRes:     make(map[string]interface{}),
+		expRes:     json.RawMessage("{}"),
 		expErr:     nil,
 	},
 	{
@@ -128,7 +128,7 @@ var testParseResponseInput = [...]struct {
 		statusCode: 200,
 		op:         "Dummy",
 		endpoint:   "dummyURL",
-		expRes:     make([]interface{}, 0),
+		expRes:     json.RawMessage("[]"),
 		expErr:     nil,
 	},
 	{
@@ -238,7 +238,7 @@ func TestParseResponse(t *testing.T) {
 var testFetchJSONInput = [...]struct {
 	transport http.RoundTripper
 	endpoint  string
-	expRes    interface{}
+	expRes    json.RawMessage
 	expErr    error
 }{
 	{
@@ -266,7 +266,7 @@ var testFetchJSONInput = [...]struct {
 	{
 		transport: http.NewFileTransport(http.Dir("testdata")),
 		endpoint:  "data.json",
-		expRes:    make(map[string]interface{}),
+		expRes:    json.RawMessage("{}"),
 		expErr:    nil,
 	},
 }
@@ -307,7 +307,7 @@ var testExchangeJSONInput = [...]struct {
 	transport http.RoundTripper
 	endpoint  string
 	data      interface{}
-	expRes    interface{}
+	expRes    json.RawMessage
 	expErr    error
 }{
 	{
@@ -345,7 +345,7 @@ var testExchangeJSONInput = [...]struct {
 		transport: http.NewFileTransport(http.Dir("testdata")),
 		endpoint:  "data.json",
 		data:      make(map[string]interface{}),
-		expRes:    make(map[string]interface{}),
+		expRes:    json.RawMessage("{}"),
 		expErr:    nil,
 	},
 }
@@ -382,6 +382,30 @@ func TestExchangeJSONContextUsed(t *testing.T) {
 	}
 }
 
+func TestClientWait(t *testing.T) {
+	ctx := context.Background()
+	ct := CtxStoreTransport{}
+
+	var waited context.Context
+	c := &Client{
+		HTTPClient: &http.Client{Transport: &ct},
+		Wait: func(ctx context.Context, retry int) error {
+			waited = ctx
+			return testError
+		},
+	}
+
+	if _, err := c.FetchJSON(ctx, "dummyURL"); err != testError {
+		t.Errorf("Client.FetchJSON(ctx, \"dummyURL\") returned %s; want error from Wait", p(err))
+	}
+	if waited != ctx {
+		t.Error("Client.FetchJSON(ctx, \"dummyURL\") didn't pass context to Wait")
+	}
+	if ct.Context != nil {
+		t.Error("Client.FetchJSON(ctx, \"dummyURL\") performed request despite Wait failing")
+	}
+}
+
 /*************
 * TEST UTILS *
 *************/
@@ -0,0 +1,253 @@
+package internal
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A FormatError reports that JSON data isn't structured as expected.
+type FormatError struct {
+	// Path locates the offending value as a dot-separated sequence of object
+	// keys and array indices, e.g. "properties.0.value". It is empty if the
+	// offending value is the JSON document itself.
+	Path string
+	// Expected describes the value expected, usually a JSON type such as
+	// "string" or "array".
+	Expected string
+	// Actual describes the value found, usually a JSON type such as "number"
+	// or "null", or "missing" if the value is absent.
+	Actual string
+}
+
+func (e *FormatError) Error() string {
+	msg := "unknown JSON data format: "
+	if e.Path != "" {
+		msg += "at " + e.Path + ": "
+	}
+	return msg + "got " + e.Actual + ", want " + e.Expected
+}
+
+// Missing returns a *FormatError reporting that the value of type expected at
+// path is absent.
+func Missing(path, expected string) *FormatError {
+	return &FormatError{Path: path, Expected: expected, Actual: "missing"}
+}
+
+// AtPath returns err with path prepended to its Path if err is a
+// *FormatError, and err unchanged otherwise.
+func AtPath(err error, path string) error {
+	e, ok := err.(*FormatError)
+	if !ok || path == "" {
+		return err
+	}
+	ne := *e
+	if ne.Path == "" {
+		ne.Path = path
+	} else {
+		ne.Path = path + "." + ne.Path
+	}
+	return &ne
+}
+
+// Decode parses the JSON document data into the value pointed to by v, which
+// should be of a typed struct, slice or map mirroring the expected structure
+// of data. If a JSON value doesn't match the type of its destination, a
+// *FormatError is returned. Other errors, e.g. for malformed JSON, are
+// returned as reported by encoding/json.
+func Decode(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if e, ok := err.(*json.UnmarshalTypeError); ok {
+		// The path reported by encoding/json varies between Go releases, so
+		// the offending value is located separately. Type errors are rare,
+		// so decoding isn't slowed down by keeping track of the path.
+		if ferr := locate(data, reflect.TypeOf(v).Elem(), ""); ferr != nil {
+			return ferr
+		}
+		return &FormatError{Expected: jsonType(e.Type), Actual: valueType(e.Value)}
+	}
+	return err
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// locate returns a *FormatError for the first value of data, which is
+// located at path, that doesn't match the type t it is decoded into. Object
+// members are searched in the order of the struct fields or sorted map keys
+// they are decoded into. locate returns nil if no such value is found.
+func locate(data []byte, t reflect.Type, path string) *FormatError {
+	if p := reflect.PtrTo(t); p.Implements(jsonUnmarshalerType) || p.Implements(textUnmarshalerType) {
+		return locateLeaf(data, t, path)
+	}
+	kind := kindOf(data)
+	if kind == "null" {
+		return nil // Decoding null is a no-op
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return locate(data, t.Elem(), path)
+	case reflect.Interface:
+		return nil
+	case reflect.Struct:
+		var members map[string]json.RawMessage
+		if json.Unmarshal(data, &members) != nil {
+			return &FormatError{Path: path, Expected: "object", Actual: kind}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := fieldName(f)
+			if name == "" {
+				continue
+			}
+			if m, ok := member(members, name); ok {
+				if err := locate(m, f.Type, join(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case reflect.Map:
+		var members map[string]json.RawMessage
+		if json.Unmarshal(data, &members) != nil {
+			return &FormatError{Path: path, Expected: "object", Actual: kind}
+		}
+		keys := make([]string, 0, len(members))
+		for k := range members {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := locate(members[k], t.Elem(), join(path, k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			break // []byte is decoded from a base64 encoded string
+		}
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return &FormatError{Path: path, Expected: "array", Actual: kind}
+		}
+		for i, e := range elems {
+			if err := locate(e, t.Elem(), join(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return locateLeaf(data, t, path)
+}
+
+// locateLeaf is like locate for values which are decoded as a whole.
+func locateLeaf(data []byte, t reflect.Type, path string) *FormatError {
+	err := json.Unmarshal(data, reflect.New(t).Interface())
+	if e, ok := err.(*json.UnmarshalTypeError); ok {
+		return &FormatError{Path: path, Expected: jsonType(e.Type), Actual: valueType(e.Value)}
+	}
+	return nil
+}
+
+// fieldName returns the object key a struct field is decoded from, or "" if
+// the field isn't decoded.
+func fieldName(f reflect.StructField) string {
+	if f.PkgPath != "" { // Unexported
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "" {
+		return f.Name
+	}
+	return tag
+}
+
+// member returns the member of members with the given key, preferring an
+// exact match like encoding/json does.
+func member(members map[string]json.RawMessage, key string) (json.RawMessage, bool) {
+	if m, ok := members[key]; ok {
+		return m, true
+	}
+	for k, m := range members {
+		if strings.EqualFold(k, key) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// kindOf returns the JSON type of the JSON value data.
+func kindOf(data []byte) string {
+	for _, c := range data {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{':
+			return "object"
+		case '[':
+			return "array"
+		case '"':
+			return "string"
+		case 't', 'f':
+			return "boolean"
+		case 'n':
+			return "null"
+		default:
+			return "number"
+		}
+	}
+	return "missing"
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonType returns the JSON type decoded into values of type t.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return t.String()
+	}
+}
+
+// valueType converts the description of a JSON value reported by a
+// json.UnmarshalTypeError into JSON terms. Descriptions such as "number 1.5",
+// reported for non-integral numbers decoded into integers, are kept as is.
+func valueType(v string) string {
+	if v == "bool" {
+		return "boolean"
+	}
+	return v
+}
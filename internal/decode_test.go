@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testDocument struct {
+	Name    string            `json:"name"`
+	Count   int64             `json:"count"`
+	Ratio   float64           `json:"ratio"`
+	Enabled bool              `json:"enabled"`
+	Items   []testItem        `json:"items"`
+	Labels  map[string]string `json:"labels"`
+	Ptr     *testItem         `json:"ptr"`
+}
+
+type testItem struct {
+	ID string `json:"id"`
+}
+
+var testDecodeInput = [...]struct {
+	json   string
+	expErr error
+}{
+	{
+		json:   `{"name":"a","count":1,"ratio":0.5,"enabled":true,"items":[{"id":"x"}],"labels":{"k":"v"}}`,
+		expErr: nil,
+	},
+	{
+		json:   `{"unknown":[1,2,3]}`,
+		expErr: nil,
+	},
+	{
+		json:   `[]`,
+		expErr: &FormatError{Path: "", Expected: "object", Actual: "array"},
+	},
+	{
+		json:   `{"name":42}`,
+		expErr: &FormatError{Path: "name", Expected: "string", Actual: "number"},
+	},
+	{
+		json:   `{"count":1.5}`,
+		expErr: &FormatError{Path: "count", Expected: "integer", Actual: "number 1.5"},
+	},
+	{
+		json:   `{"ratio":"high"}`,
+		expErr: &FormatError{Path: "ratio", Expected: "number", Actual: "string"},
+	},
+	{
+		json:   `{"enabled":"yes"}`,
+		expErr: &FormatError{Path: "enabled", Expected: "boolean", Actual: "string"},
+	},
+	{
+		json:   `{"items":{}}`,
+		expErr: &FormatError{Path: "items", Expected: "array", Actual: "object"},
+	},
+	{
+		json:   `{"items":[{"id":"x"},{"id":false}]}`,
+		expErr: &FormatError{Path: "items.1.id", Expected: "string", Actual: "boolean"},
+	},
+	{
+		json:   `{"labels":{"k":["v"]}}`,
+		expErr: &FormatError{Path: "labels.k", Expected: "string", Actual: "array"},
+	},
+	{
+		json:   `{"labels":{"k":1,"b":2,"a":null}}`, // Keys are checked in sorted order
+		expErr: &FormatError{Path: "labels.b", Expected: "string", Actual: "number"},
+	},
+	{
+		json:   `{"NAME":42}`, // Keys match fields case-insensitively
+		expErr: &FormatError{Path: "name", Expected: "string", Actual: "number"},
+	},
+	{
+		json:   `{"ptr":{"id":[]}}`,
+		expErr: &FormatError{Path: "ptr.id", Expected: "string", Actual: "array"},
+	},
+	{
+		json:   `{"items":[null,{"id":"x"},{"id":{}}]}`,
+		expErr: &FormatError{Path: "items.2.id", Expected: "string", Actual: "object"},
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, tc := range testDecodeInput {
+		var doc testDocument
+		if err := Decode([]byte(tc.json), &doc); !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("Decode(%s, &doc) returned %s; want %s", tc.json, p(err), p(tc.expErr))
+		}
+	}
+
+	var doc testDocument
+	if err := Decode([]byte(`{"name":`), &doc); err == nil {
+		t.Error("Decode(<malformed JSON>, &doc) succeeded; want error")
+	} else if _, ok := err.(*FormatError); ok {
+		t.Errorf("Decode(<malformed JSON>, &doc) returned %#v; want syntax error", err)
+	}
+}
+
+var testFormatErrorInput = [...]struct {
+	err    *FormatError
+	expMsg string
+}{
+	{
+		err:    &FormatError{Path: "items.1.id", Expected: "string", Actual: "boolean"},
+		expMsg: "unknown JSON data format: at items.1.id: got boolean, want string",
+	},
+	{
+		err:    &FormatError{Expected: "array", Actual: "object"},
+		expMsg: "unknown JSON data format: got object, want array",
+	},
+	{
+		err:    Missing("name", "string"),
+		expMsg: "unknown JSON data format: at name: got missing, want string",
+	},
+}
+
+func TestFormatError_Error(t *testing.T) {
+	for _, tc := range testFormatErrorInput {
+		if msg := tc.err.Error(); msg != tc.expMsg {
+			t.Errorf("%#v.Error() was %q; want %q", tc.err, msg, tc.expMsg)
+		}
+	}
+}
+
+func TestAtPath(t *testing.T) {
+	inner := &FormatError{Path: "id", Expected: "string", Actual: "missing"}
+	exp := &FormatError{Path: "items.1.id", Expected: "string", Actual: "missing"}
+	if err := AtPath(inner, "items.1"); !reflect.DeepEqual(err, exp) {
+		t.Errorf("AtPath(%#v, \"items.1\") was %#v; want %#v", inner, err, exp)
+	}
+	if inner.Path != "id" {
+		t.Errorf("AtPath(...) modified its argument; Path is %q, want \"id\"", inner.Path)
+	}
+
+	root := &FormatError{Expected: "array", Actual: "object"}
+	exp = &FormatError{Path: "items", Expected: "array", Actual: "object"}
+	if err := AtPath(root, "items"); !reflect.DeepEqual(err, exp) {
+		t.Errorf("AtPath(%#v, \"items\") was %#v; want %#v", root, err, exp)
+	}
+
+	if err := AtPath(testError, "items"); err != testError {
+		t.Errorf("AtPath(testError, \"items\") was %s; want %s", p(err), testError)
+	}
+	if err := AtPath(nil, "items"); err != nil {
+		t.Errorf("AtPath(nil, \"items\") was %s; want <nil>", err)
+	}
+}
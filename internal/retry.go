@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy determines how requests which fail with a 429 Too Many Requests
+// or 5xx server error response are retried. Retries are spaced by an
+// exponential backoff with jitter, unless the server specifies when to retry
+// using a Retry-After header. Retrying stops once MaxRetries is reached, the
+// context of the request is done or the next retry would occur after its
+// deadline.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+	// MinBackoff is the time waited before the first retry. The wait is
+	// doubled for each subsequent retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the time waited between retries, if positive. A
+	// request whose response asks for it to be retried later than that,
+	// using a Retry-After header, is not retried.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of each wait which is
+	// randomised to avoid retries from several clients being synchronised.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is the retry policy used by FetchJSON and ExchangeJSON.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+	Jitter:     0.2,
+}
+
+// backoff returns how long to wait before retrying a request which failed
+// with resp, where retry is the number of retries already performed.
+// ok is false if the request should not be retried.
+func (p *RetryPolicy) backoff(retry int, resp *http.Response) (wait time.Duration, ok bool) {
+	if p == nil || retry >= p.MaxRetries || !retryable(resp.StatusCode) {
+		return 0, false
+	}
+
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		if p.MaxBackoff > 0 && d > p.MaxBackoff {
+			return 0, false // Longer than we are willing to wait
+		}
+		return d, true // Server knows best
+	}
+
+	wait = p.MinBackoff
+	for i := 0; i < retry && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait -= time.Duration(p.Jitter * rand.Float64() * float64(wait))
+	}
+	return wait, true
+}
+
+func retryable(statusCode int) bool {
+	return statusCode == 429 || statusCode >= 500 && statusCode <= 599
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which either is a
+// number of seconds or an HTTP date, into the time to wait from now.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// retry waits before retrying a request which failed with resp. It returns
+// false if the request shouldn't be retried, in which case resp is left
+// untouched. Otherwise resp.Body is closed. If ctx is done while waiting,
+// ctx.Err() is returned.
+func (c *Client) retry(ctx context.Context, retry int, resp *http.Response) (bool, error) {
+	wait, ok := c.Retry.backoff(retry, resp)
+	if !ok {
+		return false, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		return false, nil // Retry would be too late; report failure instead
+	}
+
+	io.Copy(ioutil.Discard, resp.Body) // Allow connection to be reused
+	resp.Body.Close()
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-t.C:
+		return true, nil
+	}
+}
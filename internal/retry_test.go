@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = &RetryPolicy{
+	MaxRetries: 2,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: 2 * time.Millisecond,
+}
+
+var testClientRetryInput = [...]struct {
+	statuses []int
+	expCalls int
+	expRes   json.RawMessage
+}{
+	{
+		statuses: []int{200},
+		expCalls: 1,
+		expRes:   json.RawMessage("{}"),
+	},
+	{
+		statuses: []int{429, 503, 200},
+		expCalls: 3,
+		expRes:   json.RawMessage("{}"),
+	},
+	{
+		statuses: []int{500, 500, 500, 200},
+		expCalls: 3, // Gives up after MaxRetries
+		expRes:   nil,
+	},
+	{
+		statuses: []int{404, 200},
+		expCalls: 1, // Not retryable
+		expRes:   nil,
+	},
+}
+
+func TestClientRetry(t *testing.T) {
+	for _, tc := range testClientRetryInput {
+		for _, post := range []bool{false, true} {
+			st := &statusSequenceTransport{statuses: tc.statuses}
+			c := &Client{HTTPClient: &http.Client{Transport: st}, Retry: testRetryPolicy}
+
+			var res json.RawMessage
+			if post {
+				res, _ = c.ExchangeJSON(context.Background(), "http://example.com", "data")
+			} else {
+				res, _ = c.FetchJSON(context.Background(), "http://example.com")
+			}
+			if st.calls != tc.expCalls || !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf(
+					"Client (post: %t) with responses %v made %d requests returning %#v; want %d requests returning %#v",
+					post, tc.statuses, st.calls, res, tc.expCalls, tc.expRes,
+				)
+			}
+			for i, b := range st.bodies {
+				if post && b != "\"data\"\n" {
+					t.Errorf("Client sent body %q in request %d; want %q", b, i+1, "\"data\"\n")
+				}
+			}
+		}
+	}
+}
+
+func TestClientRetryDeadline(t *testing.T) {
+	st := &statusSequenceTransport{statuses: []int{429, 200}, retryAfter: "60"}
+	c := &Client{HTTPClient: &http.Client{Transport: st}, Retry: testRetryPolicy}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := c.FetchJSON(ctx, "http://example.com")
+	if e, ok := UnwrapFailedRequestError(err); !ok || e.StatusCode != 429 || st.calls != 1 {
+		t.Errorf("Client retried after deadline: %d requests made returning %s", st.calls, p(err))
+	}
+}
+
+func TestClientRetryCanceled(t *testing.T) {
+	st := &statusSequenceTransport{statuses: []int{503, 200}}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: st},
+		Retry:      &RetryPolicy{MaxRetries: 1, MinBackoff: time.Hour},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if _, err := c.FetchJSON(ctx, "http://example.com"); err != context.Canceled {
+		t.Errorf("Client returned %s when canceled during backoff; want %s", p(err), context.Canceled)
+	}
+}
+
+var testBackoffInput = [...]struct {
+	policy     *RetryPolicy
+	retry      int
+	status     int
+	retryAfter string
+	expWait    time.Duration
+	expOk      bool
+}{
+	{policy: nil, retry: 0, status: 429, expOk: false},
+	{policy: &RetryPolicy{MaxRetries: 3, MinBackoff: time.Second, MaxBackoff: 5 * time.Second}, retry: 0, status: 429, expWait: time.Second, expOk: true},
+	{policy: &RetryPolicy{MaxRetries: 3, MinBackoff: time.Second, MaxBackoff: 5 * time.Second}, retry: 2, status: 500, expWait: 4 * time.Second, expOk: true},
+	{policy: &RetryPolicy{MaxRetries: 9, MinBackoff: time.Second, MaxBackoff: 5 * time.Second}, retry: 8, status: 502, expWait: 5 * time.Second, expOk: true},
+	{policy: &RetryPolicy{MaxRetries: 9, MinBackoff: time.Second}, retry: 3, status: 503, expWait: 8 * time.Second, expOk: true}, // Uncapped
+	{policy: &RetryPolicy{MaxRetries: 3, MinBackoff: time.Second, MaxBackoff: 5 * time.Second}, retry: 3, status: 429, expOk: false},
+	{policy: &RetryPolicy{MaxRetries: 3, MinBackoff: time.Second, MaxBackoff: 5 * time.Second}, retry: 0, status: 400, expOk: false},
+	{policy: &RetryPolicy{MaxRetries: 3, MinBackoff: time.Second, MaxBackoff: 5 * time.Second}, retry: 0, status: 429, retryAfter: "3", expWait: 3 * time.Second, expOk: true},
+	{policy: &RetryPolicy{MaxRetries: 3, MinBackoff: time.Second, MaxBackoff: 5 * time.Second}, retry: 0, status: 429, retryAfter: "120", expOk: false},
+	{policy: &RetryPolicy{MaxRetries: 3, MinBackoff: time.Second}, retry: 0, status: 429, retryAfter: "120", expWait: 2 * time.Minute, expOk: true},
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	for _, tc := range testBackoffInput {
+		resp := &http.Response{StatusCode: tc.status, Header: make(http.Header)}
+		if tc.retryAfter != "" {
+			resp.Header.Set("Retry-After", tc.retryAfter)
+		}
+		wait, ok := tc.policy.backoff(tc.retry, resp)
+		if wait != tc.expWait || ok != tc.expOk {
+			t.Errorf(
+				"%#v.backoff(%d, <%d response, Retry-After: %q>) was %s, %t; want %s, %t",
+				tc.policy, tc.retry, tc.status, tc.retryAfter, wait, ok, tc.expWait, tc.expOk,
+			)
+		}
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 1, MinBackoff: time.Second, Jitter: 0.5}
+	resp := &http.Response{StatusCode: 503, Header: make(http.Header)}
+	for i := 0; i < 100; i++ {
+		if wait, _ := policy.backoff(0, resp); wait < 500*time.Millisecond || wait > time.Second {
+			t.Fatalf("%#v.backoff(0, <503 response>) was %s; want within [500ms, 1s]", policy, wait)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range [...]struct {
+		v     string
+		expD  time.Duration
+		expOk bool
+	}{
+		{v: "", expOk: false},
+		{v: "7", expD: 7 * time.Second, expOk: true},
+		{v: "-1", expOk: false},
+		{v: "Mon, 01 May 2017 12:00:30 GMT", expD: 30 * time.Second, expOk: true},
+		{v: "Mon, 01 May 2017 11:00:00 GMT", expD: 0, expOk: true},
+		{v: "soon", expOk: false},
+	} {
+		if d, ok := parseRetryAfter(tc.v, now); d != tc.expD || ok != tc.expOk {
+			t.Errorf("parseRetryAfter(%q, now) was %s, %t; want %s, %t", tc.v, d, ok, tc.expD, tc.expOk)
+		}
+	}
+}
+
+/*************
+* TEST UTILS *
+*************/
+
+// statusSequenceTransport responds with the next status of statuses for each
+// request and records the request bodies.
+type statusSequenceTransport struct {
+	statuses   []int
+	retryAfter string
+	calls      int
+	bodies     []string
+}
+
+func (st *statusSequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		st.bodies = append(st.bodies, string(b))
+	}
+	status := st.statuses[st.calls]
+	st.calls++
+
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}
+	if st.retryAfter != "" {
+		resp.Header.Set("Retry-After", st.retryAfter)
+	}
+	return resp, nil
+}
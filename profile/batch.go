@@ -0,0 +1,144 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of batches LoadAll loads concurrently
+// unless a Client specifies otherwise.
+const DefaultBatchConcurrency = 4
+
+// LoadAll is a wrapper around DefaultClient.LoadAll.
+func LoadAll(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
+	return DefaultClient.LoadAll(ctx, usernames...)
+}
+
+// LoadAll is like LoadMany, except any number of profiles may be loaded.
+// Usernames are deduplicated case-insensitively and split into batches of at
+// most LoadManyMaxSize usernames, of which at most c.BatchConcurrency are
+// loaded concurrently. Each batch is subject to c.Limiter like any other
+// request. ctx must be non-nil.
+//
+// NB! Without a Limiter, LoadAll performs its requests as fast as
+// c.BatchConcurrency allows, so loading many usernames quickly exceeds
+// Mojang's rate limits. Callers must set c.Limiter, e.g. to NewLimiter(true),
+// to stay within them.
+//
+// A batch failing to load doesn't affect the others. ps holds the profiles of
+// every batch which loaded successfully, in the order their usernames first
+// were given. If any batch failed, err is a BatchErrors listing the failures.
+//
+// Empty usernames are ignored. If any other username is malformed, an
+// *InvalidUsernameError is returned before any batch is loaded.
+func (c *Client) LoadAll(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
+	for _, u := range usernames {
+		if u == "" {
+			continue
+		}
+		if err = c.validateUsername(u); err != nil {
+			return nil, err
+		}
+	}
+
+	batches := splitBatches(usernames)
+	results := make([][]*Profile, len(batches))
+	errs := make([]error, len(batches))
+
+	concurrency := c.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, batch []string) {
+			defer func() { <-sem; wg.Done() }()
+			results[i], errs[i] = c.LoadMany(ctx, batch...)
+		}(i, batch)
+	}
+	wg.Wait()
+
+	var berrs BatchErrors
+	for i, res := range results {
+		if errs[i] != nil {
+			berrs = append(berrs, &BatchError{Usernames: batches[i], Err: errs[i]})
+			continue
+		}
+		ps = append(ps, sortByUsernames(res, batches[i])...)
+	}
+	if berrs != nil {
+		return ps, berrs
+	}
+	return ps, nil
+}
+
+// splitBatches removes empty and case-insensitive duplicate usernames and
+// splits the remaining into batches of at most LoadManyMaxSize usernames.
+func splitBatches(usernames []string) (batches [][]string) {
+	seen := make(map[string]bool, len(usernames))
+	var batch []string
+	for _, u := range usernames {
+		key := strings.ToLower(u)
+		if u == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		batch = append(batch, u)
+		if len(batch) == LoadManyMaxSize {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if batch != nil {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// sortByUsernames orders ps as their usernames appear in usernames.
+func sortByUsernames(ps []*Profile, usernames []string) []*Profile {
+	byName := make(map[string]*Profile, len(ps))
+	for _, p := range ps {
+		byName[strings.ToLower(p.Name)] = p
+	}
+	sorted := make([]*Profile, 0, len(ps))
+	for _, u := range usernames {
+		if p, ok := byName[strings.ToLower(u)]; ok {
+			sorted = append(sorted, p)
+		}
+	}
+	return sorted
+}
+
+// A BatchError reports the failure to load a batch of profiles.
+type BatchError struct {
+	Usernames []string // Usernames of the batch which failed to load.
+	Err       error    // Cause of the failure.
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("minecraft/profile: failed to load batch of %d usernames: %s", len(e.Usernames), e.Err)
+}
+
+// BatchErrors is returned by LoadAll when one or more batches fail to load.
+type BatchErrors []*BatchError
+
+func (e BatchErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "minecraft/profile: %d batches failed to load:", len(e))
+	for _, err := range e {
+		fmt.Fprintf(&buf, "\n\t%d usernames: %s", len(err.Usernames), err.Err)
+	}
+	return buf.String()
+}
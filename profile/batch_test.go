@@ -0,0 +1,151 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoadAll(t *testing.T) {
+	t.Parallel()
+
+	var usernames, expNames []string
+	for i := 0; i < 2*LoadManyMaxSize+50; i++ {
+		u := fmt.Sprintf("user%d", i)
+		usernames = append(usernames, u, strings.ToUpper(u), "") // Duplicates are dropped
+		if i%10 != 0 {
+			expNames = append(expNames, u)
+		}
+	}
+	usernames = append(usernames, "failingBatch")
+
+	bt := &bulkTransport{fail: "failingBatch"}
+	c := &Client{
+		HTTPClient:       &http.Client{Transport: bt},
+		BatchConcurrency: 2,
+	}
+	ps, err := c.LoadAll(context.Background(), usernames...)
+
+	var names []string
+	for _, p := range ps {
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual(names, expNames[:2*LoadManyMaxSize*9/10]) {
+		t.Errorf("LoadAll(ctx, ...) loaded profiles %v; want %v", names, expNames[:2*LoadManyMaxSize*9/10])
+	}
+
+	berrs, ok := err.(BatchErrors)
+	if !ok || len(berrs) != 1 {
+		t.Fatalf("LoadAll(ctx, ...) returned error %s; want BatchErrors for one batch", p(err))
+	}
+	if batch := berrs[0].Usernames; len(batch) != 51 || batch[50] != "failingBatch" {
+		t.Errorf("LoadAll(ctx, ...) reported failed batch %v; want the last batch", batch)
+	}
+	if bt.calls != 3 {
+		t.Errorf("LoadAll(ctx, ...) made %d requests; want 3", bt.calls)
+	}
+	if bt.maxInFlight > c.BatchConcurrency {
+		t.Errorf("LoadAll(ctx, ...) loaded %d batches concurrently; want at most %d", bt.maxInFlight, c.BatchConcurrency)
+	}
+}
+
+func TestLoadAllEmpty(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{HTTPClient: &http.Client{Transport: errorTransport{testError}}}
+	ps, err := c.LoadAll(context.Background(), "", "")
+	if ps != nil || err != nil {
+		t.Errorf("LoadAll(ctx, \"\", \"\") was %v, %s; want [], <nil>", ps, p(err))
+	}
+}
+
+func TestLoadAllInvalidUsername(t *testing.T) {
+	t.Parallel()
+
+	bt := &bulkTransport{}
+	c := &Client{HTTPClient: &http.Client{Transport: bt}}
+	ps, err := c.LoadAll(context.Background(), "nergalic", "not valid")
+	if exp := (&InvalidUsernameError{Username: "not valid"}); ps != nil || !reflect.DeepEqual(err, exp) {
+		t.Errorf("LoadAll(ctx, \"nergalic\", \"not valid\") was %v, %s; want [], %s", ps, p(err), exp)
+	}
+	if bt.calls != 0 {
+		t.Errorf("LoadAll(ctx, \"nergalic\", \"not valid\") sent %d requests; want 0", bt.calls)
+	}
+}
+
+func TestBatchErrors_Error(t *testing.T) {
+	err1 := &BatchError{Usernames: []string{"a", "b"}, Err: testError}
+	err2 := &BatchError{Usernames: []string{"c"}, Err: ErrTooManyRequests}
+
+	if msg := (BatchErrors{err1}).Error(); msg != err1.Error() {
+		t.Errorf("BatchErrors{err1}.Error() was %q; want %q", msg, err1.Error())
+	}
+	msg := (BatchErrors{err1, err2}).Error()
+	if !strings.Contains(msg, testError.Error()) || !strings.Contains(msg, ErrTooManyRequests.Error()) {
+		t.Errorf("BatchErrors{err1, err2}.Error() was %q; want message containing both errors", msg)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// bulkTransport answers bulk username lookups. Profiles exist for every
+// username except each tenth. Batches containing fail are rejected with a
+// 500 response.
+type bulkTransport struct {
+	fail string
+
+	mu          sync.Mutex
+	calls       int
+	inFlight    int
+	maxInFlight int
+}
+
+func (bt *bulkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bt.mu.Lock()
+	bt.calls++
+	bt.inFlight++
+	if bt.inFlight > bt.maxInFlight {
+		bt.maxInFlight = bt.inFlight
+	}
+	bt.mu.Unlock()
+	defer func() {
+		bt.mu.Lock()
+		bt.inFlight--
+		bt.mu.Unlock()
+	}()
+
+	var names []string
+	json.NewDecoder(req.Body).Decode(&names)
+
+	status := 200
+	var res []map[string]interface{}
+	for _, n := range names {
+		if n == bt.fail {
+			status = 500
+		}
+		var num int
+		if _, err := fmt.Sscanf(n, "user%d", &num); err == nil && num%10 != 0 {
+			res = append(res, map[string]interface{}{
+				"id":   fmt.Sprintf("%032x", num),
+				"name": n,
+			})
+		}
+	}
+	body, _ := json.Marshal(res)
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
@@ -1,73 +1,84 @@
 package profile
 
 import (
-	"bytes"
 	"encoding/base64"
-	"encoding/json"
+	"strconv"
 	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
 )
 
 var emptyHist = make([]PastName, 0, 0)
 
-// fillProfile fills out p with basic profile information from m.
-// m MUST contain string values for the keys "id" and "name".
-// If available, "demo" and "legacy" MUST map to boolean values.
-// fillProfile returns false if m represents a demo profile, otherwise
-// true. If fillProfile returns false, p will not have been modified.
-func fillProfile(p *Profile, m map[string]interface{}) bool {
+// fillProfile fills out p with basic profile information from js.
+// js MUST have a valid profile ID and a name, or a *internal.FormatError is
+// returned. fillProfile returns false if js represents a demo profile,
+// otherwise true. If fillProfile doesn't return true, p will not have been
+// modified.
+func fillProfile(p *Profile, js *profileJSON) (bool, error) {
 	// Ensure demo accounts are not returned
-	if t, demo := m["demo"]; demo && t.(bool) {
-		return false
+	if js.Demo {
+		return false, nil
 	}
 
-	id := m["id"].(string)
-	name := m["name"].(string)
+	if js.ID == "" {
+		return false, internal.Missing("id", "string")
+	}
+	id, err := ParseID(js.ID)
+	if err != nil {
+		return false, &internal.FormatError{Path: "id", Expected: "profile ID", Actual: strconv.Quote(js.ID)}
+	}
+	if js.Name == "" {
+		return false, internal.Missing("name", "string")
+	}
 
 	if p.NameHistory == nil {
 		// Legacy Minecraft accounts have not migrated to Mojang accounts.
 		// To change your Minecraft username you need to have a Mojang account.
 		// Hence "legacy" flags a profile as having no name history.
-		if t, legacy := m["legacy"]; legacy && t.(bool) {
+		if js.Legacy {
 			p.NameHistory = emptyHist
 		}
 	}
 
 	p.ID = id
-	p.Name = name
+	p.Name = js.Name
 
-	return true
+	return true, nil
 }
 
 // buildHistory creates a username history (previous username first, original
 // username last) and returns it along with the current username.
-// a is an array of maps containing "name" and (possibly) "changedToAt" keys.
-// The "name" values MUST be string and the "changedToAt" values MUST be integer.
-// A "changedToAt" field is the "until" field of the previous PastName struct.
-func buildHistory(arr []interface{}) (name string, hist []PastName) {
+// arr is a name history served by Mojang, original username first, in which
+// every entry MUST have a name. An entry's changedToAt field is the Until
+// field of the previous PastName struct.
+func buildHistory(arr []pastNameJSON) (name string, hist []PastName, err error) {
 	if len(arr) == 0 {
-		return "", nil
+		return "", nil, nil
 	}
 
 	hist = make([]PastName, len(arr)-1)
 
 	h := len(hist) - 1
 	for i, v := range arr {
-		m := v.(map[string]interface{})
+		if v.Name == "" {
+			return "", nil, internal.Missing(strconv.Itoa(i)+".name", "string")
+		}
 
-		if v, ok := m["changedToAt"]; ok && i > 0 {
-			hist[h+1].Until = msToTime(int64(v.(float64)))
+		if v.ChangedToAt != 0 && i > 0 {
+			hist[h+1].Until = msToTime(v.ChangedToAt)
 		}
 
 		if i == len(hist) {
-			name = m["name"].(string)
+			name = v.Name
 			break
 		} else {
-			hist[h].Name = m["name"].(string)
+			hist[h].Name = v.Name
 			h--
 		}
 	}
 
-	return name, hist
+	return name, hist, nil
 }
 
 func msToTime(ms int64) time.Time {
@@ -76,20 +87,29 @@ func msToTime(ms int64) time.Time {
 	return time.Unix(s, ns)
 }
 
-// buildProperties returns a property set based on a JSON array of properties.
-// props MUST consist of map[string]interface{} maps, each map containing
-// string values for the keys "name" and "value".
-func buildProperties(props []interface{}) (ps *Properties, err error) {
+func timeToMs(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// buildProperties returns a property set based on a JSON array of properties,
+// each of which MUST have a name. If raw is true, the properties are also kept
+// as received in ps.Raw. Format errors within the decoded value of a property
+// are reported with paths continuing from the path of the value.
+func buildProperties(props []propertyJSON, raw bool) (ps *Properties, err error) {
 	ps = &Properties{}
-	for _, p := range props {
-		prop := p.(map[string]interface{})
-		name := prop["name"].(string)
-		value := prop["value"].(string) // base64 encoded
+	for i, prop := range props {
+		if prop.Name == "" {
+			return nil, internal.Missing(strconv.Itoa(i)+".name", "string")
+		}
+
+		if raw {
+			ps.Raw = append(ps.Raw, Property{Name: prop.Name, Value: prop.Value, Signature: prop.Signature})
+		}
 
-		if parser, ok := propertyPopulators[name]; ok {
-			err = parser(value, ps)
+		if parser, ok := propertyPopulators[prop.Name]; ok {
+			err = parser(prop.Value, ps)
 			if err != nil {
-				return nil, err
+				return nil, internal.AtPath(err, strconv.Itoa(i)+".value")
 			}
 		}
 	}
@@ -104,42 +124,63 @@ var propertyPopulators = map[string]func(base64 string, p *Properties) error{
 }
 
 // populateTextures parses the base64 encoded "textures" property enc and adds
-// its information to the Properties struct.
+// its information to the Properties struct. If the decoded JSON isn't
+// structured as expected, a *internal.FormatError with a path relative to it
+// is returned.
 func populateTextures(enc string, props *Properties) error {
 	bs, err := base64.StdEncoding.DecodeString(enc)
 	if err != nil {
 		return err
 	}
 
-	var j map[string]interface{}
-	err = json.NewDecoder(bytes.NewBuffer(bs)).Decode(&j)
-	if err != nil {
+	var js texturesJSON
+	if err = internal.Decode(bs, &js); err != nil {
 		return err
 	}
 
-	ts := j["textures"].(map[string]interface{})
+	if js.ProfileID == "" {
+		return internal.Missing("profileId", "string")
+	}
+	id, err := ParseID(js.ProfileID)
+	if err != nil {
+		return &internal.FormatError{Path: "profileId", Expected: "profile ID", Actual: strconv.Quote(js.ProfileID)}
+	}
+	if js.Textures == nil {
+		return internal.Missing("textures", "object")
+	}
+	t := &Textures{
+		ProfileID:         id,
+		ProfileName:       js.ProfileName,
+		SignatureRequired: js.SignatureRequired,
+		Textures:          make(map[string]Texture, len(js.Textures)),
+	}
+	if js.Timestamp != 0 {
+		t.Timestamp = msToTime(js.Timestamp)
+	}
+	for typ, v := range js.Textures {
+		if v.URL == "" {
+			return internal.Missing("textures."+typ+".url", "string")
+		}
+		t.Textures[typ] = Texture{URL: v.URL, Hash: textureHash(v.URL), Metadata: v.Metadata}
+	}
+	props.Textures = t
 
 	// Set skin URL and skin Model if present
-	if s, set := ts["SKIN"]; set {
-		skin := s.(map[string]interface{})
-		props.SkinURL = skin["url"].(string)
+	if skin, set := t.Skin(); set {
+		props.SkinURL = skin.URL
 
 		props.Model = Steve // Steve unless explicitly overridden
-		if s, set := skin["metadata"]; set {
-			skinMeta := s.(map[string]interface{})
-			if m, set := skinMeta["model"]; set && m.(string) == "slim" {
-				props.Model = Alex
-			}
+		if skin.Metadata["model"] == "slim" {
+			props.Model = Alex
 		}
 	} else {
 		// Default skin and model depends on player ID
-		props.Model = defaultModel(j["profileId"].(string))
+		props.Model = defaultModel(id)
 	}
 
 	// Set cape URL
-	if c, ok := ts["CAPE"]; ok {
-		cape := c.(map[string]interface{})
-		props.CapeURL = cape["url"].(string)
+	if cape, set := t.Cape(); set {
+		props.CapeURL = cape.URL
 	}
 
 	return nil
@@ -167,7 +208,7 @@ func populateTextures(enc string, props *Properties) error {
 // LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 // SOFTWARE.
-func defaultModel(uuid string) Model {
+func defaultModel(uuid ID) Model {
 	if (isEven(uuid[7]) != isEven(uuid[16+7])) != (isEven(uuid[15]) != isEven(uuid[16+15])) {
 		return Alex
 	} else {
@@ -2,42 +2,45 @@ package profile
 
 import (
 	"encoding/base64"
-	"io"
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
 )
 
 var testFillProfileInput = [...]struct {
 	p          Profile
-	m          map[string]interface{}
+	js         profileJSON
 	expProfile Profile
 	isDemo     bool
+	expErr     error
 }{
 	{
 		p: Profile{ID: "x", Name: "y"},
-		m: map[string]interface{}{
-			"demo": true,
+		js: profileJSON{
+			Demo: true,
 		},
 		expProfile: Profile{ID: "x", Name: "y"},
 		isDemo:     true,
 	},
 	{
 		p: Profile{ID: "x", Name: "y"},
-		m: map[string]interface{}{
-			"id":   "087cc153c3434ff7ac497de1569affa1",
-			"name": "Nergalic",
-			"demo": true,
+		js: profileJSON{
+			ID:   "087cc153c3434ff7ac497de1569affa1",
+			Name: "Nergalic",
+			Demo: true,
 		},
 		expProfile: Profile{ID: "x", Name: "y"},
 		isDemo:     true,
 	},
 	{
 		p: Profile{ID: "x", Name: "y"},
-		m: map[string]interface{}{
-			"id":   "cabefc91b5df4c87886a6c604da2e46f",
-			"name": "AxeLaw",
-			"demo": false,
+		js: profileJSON{
+			ID:   "cabefc91b5df4c87886a6c604da2e46f",
+			Name: "AxeLaw",
+			Demo: false,
 		},
 		expProfile: Profile{
 			ID:   "cabefc91b5df4c87886a6c604da2e46f",
@@ -46,9 +49,9 @@ var testFillProfileInput = [...]struct {
 	},
 	{
 		p: Profile{ID: "x", Name: "y"},
-		m: map[string]interface{}{
-			"id":   "087cc153c3434ff7ac497de1569affa1",
-			"name": "Nergalic",
+		js: profileJSON{
+			ID:   "087cc153c3434ff7ac497de1569affa1",
+			Name: "Nergalic",
 		},
 		expProfile: Profile{
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -57,10 +60,10 @@ var testFillProfileInput = [...]struct {
 	},
 	{
 		p: Profile{},
-		m: map[string]interface{}{
-			"id":     "087cc153c3434ff7ac497de1569affa1",
-			"name":   "Nergalic",
-			"legacy": false,
+		js: profileJSON{
+			ID:     "087cc153c3434ff7ac497de1569affa1",
+			Name:   "Nergalic",
+			Legacy: false,
 		},
 		expProfile: Profile{
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -69,10 +72,10 @@ var testFillProfileInput = [...]struct {
 	},
 	{
 		p: Profile{},
-		m: map[string]interface{}{
-			"id":     "087cc153c3434ff7ac497de1569affa1",
-			"name":   "Nergalic",
-			"legacy": true,
+		js: profileJSON{
+			ID:     "087cc153c3434ff7ac497de1569affa1",
+			Name:   "Nergalic",
+			Legacy: true,
 		},
 		expProfile: Profile{
 			ID:          "087cc153c3434ff7ac497de1569affa1",
@@ -82,10 +85,10 @@ var testFillProfileInput = [...]struct {
 	},
 	{ // Existing name history not overwritten
 		p: Profile{NameHistory: make([]PastName, 1)},
-		m: map[string]interface{}{
-			"id":     "087cc153c3434ff7ac497de1569affa1",
-			"name":   "Nergalic",
-			"legacy": true,
+		js: profileJSON{
+			ID:     "087cc153c3434ff7ac497de1569affa1",
+			Name:   "Nergalic",
+			Legacy: true,
 		},
 		expProfile: Profile{
 			ID:          "087cc153c3434ff7ac497de1569affa1",
@@ -93,30 +96,57 @@ var testFillProfileInput = [...]struct {
 			NameHistory: make([]PastName, 1),
 		},
 	},
+	{
+		p: Profile{ID: "x", Name: "y"},
+		js: profileJSON{
+			Name: "Nergalic",
+		},
+		expProfile: Profile{ID: "x", Name: "y"},
+		expErr:     &internal.FormatError{Path: "id", Expected: "string", Actual: "missing"},
+	},
+	{
+		p: Profile{ID: "x", Name: "y"},
+		js: profileJSON{
+			ID:   "not-an-id",
+			Name: "Nergalic",
+		},
+		expProfile: Profile{ID: "x", Name: "y"},
+		expErr:     &internal.FormatError{Path: "id", Expected: "profile ID", Actual: `"not-an-id"`},
+	},
+	{
+		p: Profile{ID: "x", Name: "y"},
+		js: profileJSON{
+			ID: "087cc153c3434ff7ac497de1569affa1",
+		},
+		expProfile: Profile{ID: "x", Name: "y"},
+		expErr:     &internal.FormatError{Path: "name", Expected: "string", Actual: "missing"},
+	},
 }
 
 func TestFillProfile(t *testing.T) {
 	for _, tc := range testFillProfileInput {
 		profile := tc.p
-		notDemo := fillProfile(&profile, tc.m)
-		if !reflect.DeepEqual(profile, tc.expProfile) || notDemo != !tc.isDemo {
+		notDemo, err := fillProfile(&profile, &tc.js)
+		expNotDemo := !tc.isDemo && tc.expErr == nil
+		if !reflect.DeepEqual(profile, tc.expProfile) || notDemo != expNotDemo || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"\n"+
 					"fillProfile(%#v, %#v)\n"+
-					"was  %#v, %t\n"+
-					"want %#v, %t",
-				tc.p, tc.m,
-				profile, notDemo,
-				tc.expProfile, !tc.isDemo,
+					"was  %#v, %t, %s\n"+
+					"want %#v, %t, %s",
+				tc.p, tc.js,
+				profile, notDemo, p(err),
+				tc.expProfile, expNotDemo, p(tc.expErr),
 			)
 		}
 	}
 }
 
 var testBuildHistoryInput = [...]struct {
-	arr     []interface{}
+	arr     []pastNameJSON
 	expName string
 	expHist []PastName
+	expErr  error
 }{
 	{
 		arr:     nil,
@@ -124,28 +154,21 @@ var testBuildHistoryInput = [...]struct {
 		expHist: nil,
 	},
 	{
-		arr:     []interface{}{},
+		arr:     []pastNameJSON{},
 		expName: "",
 		expHist: nil,
 	},
 	{
-		arr: []interface{}{
-			map[string]interface{}{
-				"name": "A",
-			},
+		arr: []pastNameJSON{
+			{Name: "A"},
 		},
 		expName: "A",
 		expHist: emptyHist,
 	},
 	{
-		arr: []interface{}{
-			map[string]interface{}{
-				"name": "B",
-			},
-			map[string]interface{}{
-				"name":        "A",
-				"changedToAt": float64(1423047705000),
-			},
+		arr: []pastNameJSON{
+			{Name: "B"},
+			{Name: "A", ChangedToAt: 1423047705000},
 		},
 		expName: "A",
 		expHist: []PastName{
@@ -156,18 +179,10 @@ var testBuildHistoryInput = [...]struct {
 		},
 	},
 	{
-		arr: []interface{}{
-			map[string]interface{}{
-				"name": "C",
-			},
-			map[string]interface{}{
-				"name":        "B",
-				"changedToAt": float64(1000047705000),
-			},
-			map[string]interface{}{
-				"name":        "A",
-				"changedToAt": float64(1423047705000),
-			},
+		arr: []pastNameJSON{
+			{Name: "C"},
+			{Name: "B", ChangedToAt: 1000047705000},
+			{Name: "A", ChangedToAt: 1423047705000},
 		},
 		expName: "A",
 		expHist: []PastName{
@@ -181,20 +196,29 @@ var testBuildHistoryInput = [...]struct {
 			},
 		},
 	},
+	{
+		arr: []pastNameJSON{
+			{Name: "B"},
+			{ChangedToAt: 1423047705000},
+		},
+		expName: "",
+		expHist: nil,
+		expErr:  &internal.FormatError{Path: "1.name", Expected: "string", Actual: "missing"},
+	},
 }
 
 func TestBuildHistory(t *testing.T) {
 	for _, tc := range testBuildHistoryInput {
-		name, hist := buildHistory(tc.arr)
-		if name != tc.expName || !reflect.DeepEqual(hist, tc.expHist) {
+		name, hist, err := buildHistory(tc.arr)
+		if name != tc.expName || !reflect.DeepEqual(hist, tc.expHist) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"\n"+
 					"buildHistory(%#v)\n"+
-					"was  %q, %#v\n"+
-					"want %q, %#v",
+					"was  %q, %#v, %s\n"+
+					"want %q, %#v, %s",
 				tc.arr,
-				name, hist,
-				tc.expName, tc.expHist,
+				name, hist, p(err),
+				tc.expName, tc.expHist, p(tc.expErr),
 			)
 		}
 	}
@@ -226,7 +250,7 @@ func TestIsEven(t *testing.T) {
 }
 
 var testDefaultModelInput = [...]struct {
-	uuid     string
+	uuid     ID
 	expModel Model
 }{
 	{
@@ -260,7 +284,27 @@ var testPopulateTexturesInput = [...]struct {
 	{
 		enc:           "",
 		expProperties: &Properties{},
-		expErr:        io.EOF,
+		expErr:        json.Unmarshal(nil, new(texturesJSON)), // Unexpected end of JSON input
+	},
+	{
+		enc:           b64(`{"profileId":"087cc153c3434ff7ac497de1569affa1","textures":{"SKIN":{"url":42}}}`),
+		expProperties: &Properties{},
+		expErr:        &internal.FormatError{Path: "textures.SKIN.url", Expected: "string", Actual: "number"},
+	},
+	{
+		enc:           b64(`{"profileId":"087cc153c3434ff7ac497de1569affa1","textures":{"SKIN":{}}}`),
+		expProperties: &Properties{},
+		expErr:        &internal.FormatError{Path: "textures.SKIN.url", Expected: "string", Actual: "missing"},
+	},
+	{
+		enc:           b64(`{"profileId":"not-an-id","textures":{}}`),
+		expProperties: &Properties{},
+		expErr:        &internal.FormatError{Path: "profileId", Expected: "profile ID", Actual: `"not-an-id"`},
+	},
+	{
+		enc:           b64(`{"profileId":"087cc153c3434ff7ac497de1569affa1"}`),
+		expProperties: &Properties{},
+		expErr:        &internal.FormatError{Path: "textures", Expected: "object", Actual: "missing"},
 	},
 	{
 		enc: "eyJ0aW1lc3RhbXAiOjE0OTM4NzUyMDcyMDYsInByb2ZpbGVJZCI6ImQ5MGI2OGJjODE3MjQzMjlhMDQ3ZjExODZkY2Q0MzM2IiwicHJvZmlsZU5hbWUiOiJha3Jvbm1hbjEiLCJ0ZXh0dXJlcyI6eyJTS0lOIjp7InVybCI6Imh0dHA6Ly90ZXh0dXJlcy5taW5lY3JhZnQubmV0L3RleHR1cmUvMzE3YTQxYzdhMzE1ODIxZTM2ZWU4YzdjOGMzOTQ3MTc0ZTQxYjU1MmViNDE2OGI3MTI3YzJkNWI4MmZhY2UwIn0sIkNBUEUiOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS9lYzgwYTIyNWIxNDVjODEyYTZlZjFjYTI5YWYwZjNlYmYwMjE2Mzg3NGQxYTY2ZTUzYmFjOTk5NjUyMjVlMCJ9fX0=",
@@ -268,6 +312,21 @@ var testPopulateTexturesInput = [...]struct {
 			SkinURL: "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
 			CapeURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
 			Model:   Steve,
+			Textures: &Textures{
+				Timestamp:   msToTime(1493875207206),
+				ProfileID:   "d90b68bc81724329a047f1186dcd4336",
+				ProfileName: "akronman1",
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:  "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
+						Hash: "317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
+					},
+					CapeTexture: {
+						URL:  "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
+						Hash: "ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
+					},
+				},
+			},
 		},
 	},
 	{
@@ -276,6 +335,17 @@ var testPopulateTexturesInput = [...]struct {
 			SkinURL: "http://textures.minecraft.net/texture/d72d9b00f3f6494607d20e557e7f1b276e7386bbfe69649be87bec8c448d",
 			CapeURL: "",
 			Model:   Steve,
+			Textures: &Textures{
+				Timestamp:   msToTime(1493875010181),
+				ProfileID:   "cabefc91b5df4c87886a6c604da2e46f",
+				ProfileName: "AxeLaw",
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:  "http://textures.minecraft.net/texture/d72d9b00f3f6494607d20e557e7f1b276e7386bbfe69649be87bec8c448d",
+						Hash: "d72d9b00f3f6494607d20e557e7f1b276e7386bbfe69649be87bec8c448d",
+					},
+				},
+			},
 		},
 	},
 	{
@@ -284,6 +354,18 @@ var testPopulateTexturesInput = [...]struct {
 			SkinURL: "http://textures.minecraft.net/texture/bc2e1750c04c15e5b7b1f2baffa3712134faf7744c41723517b59608e4c9568",
 			CapeURL: "",
 			Model:   Alex,
+			Textures: &Textures{
+				Timestamp:   msToTime(1493877071870),
+				ProfileID:   "36dcc7a83ca04372865782818586cb2c",
+				ProfileName: "SakuraBell",
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:      "http://textures.minecraft.net/texture/bc2e1750c04c15e5b7b1f2baffa3712134faf7744c41723517b59608e4c9568",
+						Hash:     "bc2e1750c04c15e5b7b1f2baffa3712134faf7744c41723517b59608e4c9568",
+						Metadata: map[string]string{"model": "slim"},
+					},
+				},
+			},
 		},
 	},
 	{
@@ -292,6 +374,37 @@ var testPopulateTexturesInput = [...]struct {
 			SkinURL: "",
 			CapeURL: "",
 			Model:   Steve,
+			Textures: &Textures{
+				Timestamp:   msToTime(1493877857456),
+				ProfileID:   "ec561538f3fd461daff5086b22154bce",
+				ProfileName: "Alex",
+				Textures:    map[string]Texture{},
+			},
+		},
+	},
+	{
+		enc: "eyJ0aW1lc3RhbXAiOjE1MDAwMDAwMDAxMjMsInByb2ZpbGVJZCI6IjA4N2NjMTUzYzM0MzRmZjdhYzQ5N2RlMTU2OWFmZmExIiwicHJvZmlsZU5hbWUiOiJOZXJnYWxpYyIsInNpZ25hdHVyZVJlcXVpcmVkIjp0cnVlLCJ0ZXh0dXJlcyI6eyJTS0lOIjp7InVybCI6Imh0dHA6Ly90ZXh0dXJlcy5taW5lY3JhZnQubmV0L3RleHR1cmUvYWJjMTIzIiwibWV0YWRhdGEiOnsibW9kZWwiOiJzbGltIn19LCJFTFlUUkEiOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS9kZWY0NTYifX19",
+		expProperties: &Properties{
+			SkinURL: "http://textures.minecraft.net/texture/abc123",
+			CapeURL: "",
+			Model:   Alex,
+			Textures: &Textures{
+				Timestamp:         msToTime(1500000000123),
+				ProfileID:         "087cc153c3434ff7ac497de1569affa1",
+				ProfileName:       "Nergalic",
+				SignatureRequired: true,
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:      "http://textures.minecraft.net/texture/abc123",
+						Hash:     "abc123",
+						Metadata: map[string]string{"model": "slim"},
+					},
+					ElytraTexture: {
+						URL:  "http://textures.minecraft.net/texture/def456",
+						Hash: "def456",
+					},
+				},
+			},
 		},
 	},
 }
@@ -300,7 +413,7 @@ func TestPopulateTextures(t *testing.T) {
 	for _, tc := range testPopulateTexturesInput {
 		var p Properties
 		err := populateTextures(tc.enc, &p)
-		if !reflect.DeepEqual(&p, tc.expProperties) || err != tc.expErr {
+		if !reflect.DeepEqual(&p, tc.expProperties) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"populateTextures(%q, Properties) produced result:\n"+
 					"      %#v, %s\n"+
@@ -314,45 +427,69 @@ func TestPopulateTextures(t *testing.T) {
 }
 
 var testBuildPropertiesInput = [...]struct {
-	props         []interface{}
+	props         []propertyJSON
 	expProperties *Properties
 	expErr        error
 }{
 	{
-		props:         []interface{}{},
+		props:         []propertyJSON{},
 		expProperties: &Properties{},
 	},
 	{
-		props: []interface{}{
-			map[string]interface{}{
-				"name":  "nonExistingProperty",
-				"value": "dummy",
-			},
+		props: []propertyJSON{
+			{Name: "nonExistingProperty", Value: "dummy"},
 		},
 		expProperties: &Properties{},
 	},
 	{
-		props: []interface{}{
-			map[string]interface{}{
-				"name":  "textures",
-				"value": "!notBase64",
-			},
+		props: []propertyJSON{
+			{Name: "textures", Value: "!notBase64"},
 		},
 		expProperties: nil,
 		expErr:        base64.CorruptInputError(0),
 	},
 	{
-		props: []interface{}{
-			map[string]interface{}{
-				"name":  "textures",
-				"value": "eyJ0aW1lc3RhbXAiOjE0OTM4NzUyMDcyMDYsInByb2ZpbGVJZCI6ImQ5MGI2OGJjODE3MjQzMjlhMDQ3ZjExODZkY2Q0MzM2IiwicHJvZmlsZU5hbWUiOiJha3Jvbm1hbjEiLCJ0ZXh0dXJlcyI6eyJTS0lOIjp7InVybCI6Imh0dHA6Ly90ZXh0dXJlcy5taW5lY3JhZnQubmV0L3RleHR1cmUvMzE3YTQxYzdhMzE1ODIxZTM2ZWU4YzdjOGMzOTQ3MTc0ZTQxYjU1MmViNDE2OGI3MTI3YzJkNWI4MmZhY2UwIn0sIkNBUEUiOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS9lYzgwYTIyNWIxNDVjODEyYTZlZjFjYTI5YWYwZjNlYmYwMjE2Mzg3NGQxYTY2ZTUzYmFjOTk5NjUyMjVlMCJ9fX0=",
+		props: []propertyJSON{
+			{
+				Name:  "textures",
+				Value: "eyJ0aW1lc3RhbXAiOjE0OTM4NzUyMDcyMDYsInByb2ZpbGVJZCI6ImQ5MGI2OGJjODE3MjQzMjlhMDQ3ZjExODZkY2Q0MzM2IiwicHJvZmlsZU5hbWUiOiJha3Jvbm1hbjEiLCJ0ZXh0dXJlcyI6eyJTS0lOIjp7InVybCI6Imh0dHA6Ly90ZXh0dXJlcy5taW5lY3JhZnQubmV0L3RleHR1cmUvMzE3YTQxYzdhMzE1ODIxZTM2ZWU4YzdjOGMzOTQ3MTc0ZTQxYjU1MmViNDE2OGI3MTI3YzJkNWI4MmZhY2UwIn0sIkNBUEUiOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS9lYzgwYTIyNWIxNDVjODEyYTZlZjFjYTI5YWYwZjNlYmYwMjE2Mzg3NGQxYTY2ZTUzYmFjOTk5NjUyMjVlMCJ9fX0=",
 			},
 		},
 		expProperties: &Properties{
 			SkinURL: "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
 			CapeURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
 			Model:   Steve,
+			Textures: &Textures{
+				Timestamp:   msToTime(1493875207206),
+				ProfileID:   "d90b68bc81724329a047f1186dcd4336",
+				ProfileName: "akronman1",
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:  "http://textures.minecraft.net/texture/317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
+						Hash: "317a41c7a315821e36ee8c7c8c3947174e41b552eb4168b7127c2d5b82face0",
+					},
+					CapeTexture: {
+						URL:  "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
+						Hash: "ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
+					},
+				},
+			},
+		},
+	},
+	{
+		props: []propertyJSON{
+			{Name: "nonExistingProperty", Value: "dummy"},
+			{Name: "textures", Value: b64(`{"profileId":"087cc153c3434ff7ac497de1569affa1","textures":{"SKIN":{"url":42}}}`)},
 		},
+		expProperties: nil,
+		expErr:        &internal.FormatError{Path: "1.value.textures.SKIN.url", Expected: "string", Actual: "number"},
+	},
+	{
+		props: []propertyJSON{
+			{Value: "dummy"},
+		},
+		expProperties: nil,
+		expErr:        &internal.FormatError{Path: "0.name", Expected: "string", Actual: "missing"},
 	},
 	// Other cases:
 	// - Multiple properties
@@ -362,8 +499,8 @@ var testBuildPropertiesInput = [...]struct {
 
 func TestBuildProperties(t *testing.T) {
 	for _, tc := range testBuildPropertiesInput {
-		ps, err := buildProperties(tc.props)
-		if !reflect.DeepEqual(ps, tc.expProperties) || err != tc.expErr {
+		ps, err := buildProperties(tc.props, false)
+		if !reflect.DeepEqual(ps, tc.expProperties) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"buildProperties(%#v)\n"+
 					"was:  %#v, %s\n"+
@@ -375,3 +512,12 @@ func TestBuildProperties(t *testing.T) {
 		}
 	}
 }
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// b64 returns the base64 encoding of s, as used for property values.
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
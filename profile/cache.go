@@ -0,0 +1,230 @@
+package profile
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Cache stores responses from the Mojang servers, allowing a Client to
+// answer repeated lookups without requesting the same data again.
+// Implementations must be safe for concurrent use by multiple goroutines.
+//
+// Keys are opaque strings chosen by the Client. Values are JSON documents
+// which must be returned exactly as they were stored.
+type Cache interface {
+	// Get returns the value stored for key. ok is false if no value is
+	// stored for key or the value has expired.
+	Get(key string) (value []byte, ok bool)
+	// Set stores value for key. The value expires once ttl has passed.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// Default time-to-live of cached responses used by a Client which doesn't
+// specify its own.
+const (
+	// DefaultCacheTTL applies to profiles looked up by username or ID, incl.
+	// their name histories.
+	DefaultCacheTTL = 10 * time.Minute
+	// DefaultPropertiesTTL applies to profile properties. It matches the rate
+	// limit of one properties request per profile per minute.
+	DefaultPropertiesTTL = time.Minute
+)
+
+// MemoryCache is an in-memory Cache which holds a bounded number of values.
+// When full, the least recently used value is evicted to make room for new
+// values. Expired values are evicted when encountered.
+type MemoryCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     list.List // Most recently used first
+
+	now func() time.Time
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns a MemoryCache which holds at most size values.
+// If size <= 0, the number of values is unbounded.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// Get returns the value stored for key, if present and not expired.
+func (c *MemoryCache) Get(key string) (value []byte, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if !c.now().Before(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value for key until ttl has passed, evicting the least recently
+// used value if the cache is full.
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*memoryEntry)
+		e.value, e.expires = value, expires
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&memoryEntry{key, value, expires})
+	if c.size > 0 && c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Len returns the number of values held by c, incl. expired values which
+// have not been evicted yet.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// DiskCache is a Cache which stores each value as a file in a directory,
+// allowing cached values to survive restarts and be shared between processes.
+// Failures to read or write files are treated as cache misses.
+type DiskCache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewDiskCache returns a DiskCache storing its values in dir. The directory
+// is created if it doesn't exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir, now: time.Now}, nil
+}
+
+// Get returns the value stored for key, if present and not expired.
+func (c *DiskCache) Get(key string) (value []byte, ok bool) {
+	bs, err := ioutil.ReadFile(c.path(key))
+	if err != nil || len(bs) < 8 {
+		return nil, false
+	}
+	expires := time.Unix(0, int64(binary.BigEndian.Uint64(bs)))
+	if !c.now().Before(expires) {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+	return bs[8:], true
+}
+
+// Set stores value for key until ttl has passed.
+func (c *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	bs := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(bs, uint64(c.now().Add(ttl).UnixNano()))
+	copy(bs[8:], value)
+	writeFileAtomic(c.path(key), bs)
+}
+
+// path returns the name of the file holding the value for key.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// writeFileAtomic replaces the file named path with one holding data. The
+// data is written to a temporary file in the same directory first, so readers
+// never observe partially written files.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Cache keys used by Client.
+func nameKey(username string) string   { return "name:" + strings.ToLower(username) }
+func idKey(id ID) string               { return "id:" + string(id) }
+func historyKey(id ID) string          { return "history:" + string(id) }
+func propertiesKey(id ID) string       { return "properties:" + string(id) }
+func signedPropertiesKey(id ID) string { return "signed-properties:" + string(id) }
+
+// cached returns the JSON document cached for key, if any.
+func (c *Client) cached(key string) (js json.RawMessage, ok bool) {
+	if c.Cache == nil || key == "" {
+		return nil, false
+	}
+	bs, ok := c.Cache.Get(key)
+	if !ok || !json.Valid(bs) {
+		return nil, false
+	}
+	return bs, true
+}
+
+// cache stores js for key, unless c has no Cache. js is stored as is if it
+// is a json.RawMessage, and JSON encoded otherwise.
+func (c *Client) cache(key string, js interface{}, ttl time.Duration) {
+	if c.Cache == nil || key == "" {
+		return
+	}
+	if raw, ok := js.(json.RawMessage); ok {
+		c.Cache.Set(key, raw, ttl)
+	} else if bs, err := json.Marshal(js); err == nil {
+		c.Cache.Set(key, bs, ttl)
+	}
+}
+
+func (c *Client) cacheTTL() time.Duration {
+	if c.CacheTTL > 0 {
+		return c.CacheTTL
+	}
+	return DefaultCacheTTL
+}
+
+func (c *Client) propertiesTTL() time.Duration {
+	if c.PropertiesTTL > 0 {
+		return c.PropertiesTTL
+	}
+	return DefaultPropertiesTTL
+}
@@ -0,0 +1,229 @@
+package profile
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	c := NewMemoryCache(2)
+	c.now = clock.Now
+
+	c.Set("a", []byte("A"), time.Minute)
+	c.Set("b", []byte("B"), time.Minute)
+	c.Get("a")                           // Make "b" least recently used
+	c.Set("c", []byte("C"), time.Second) // Evicts "b"
+
+	expectCached(t, c, "a", "A")
+	expectMissing(t, c, "b")
+	expectCached(t, c, "c", "C")
+
+	c.Set("a", []byte("A2"), time.Minute)
+	expectCached(t, c, "a", "A2")
+
+	clock.Add(time.Second)
+	expectMissing(t, c, "c")
+	expectCached(t, c, "a", "A2")
+
+	if n := c.Len(); n != 1 {
+		t.Errorf("MemoryCache.Len() was %d; want 1", n)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	c, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("NewDiskCache(%q) failed: %s", dir, err)
+	}
+	c.now = clock.Now
+
+	c.Set("a", []byte("A"), time.Minute)
+	c.Set("b", []byte("B"), time.Second)
+	expectMissing(t, c, "c")
+
+	// Values must survive across instances
+	c, _ = NewDiskCache(dir)
+	c.now = clock.Now
+	expectCached(t, c, "a", "A")
+	expectCached(t, c, "b", "B")
+
+	clock.Add(time.Second)
+	expectCached(t, c, "a", "A")
+	expectMissing(t, c, "b")
+}
+
+func TestClientCache(t *testing.T) {
+	t.Parallel()
+
+	rt := &countingTransport{transport: http.NewFileTransport(http.Dir("testdata"))}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: rt},
+		Cache:      NewMemoryCache(0),
+	}
+	ctx := context.Background()
+	exp := &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}
+
+	for i := 0; i < 2; i++ {
+		p, err := c.Load(ctx, "nergalic")
+		if !reflect.DeepEqual(p, exp) || err != nil {
+			t.Fatalf("Load(ctx, \"nergalic\") was %#v, %s; want %#v, <nil>", p, err, exp)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		p, err := c.LoadWithProperties(ctx, string(exp.ID))
+		if err != nil || p.Properties == nil {
+			t.Fatalf("LoadWithProperties(ctx, %q) failed: %s", exp.ID, err)
+		}
+	}
+	if n := rt.Count(); n != 2 {
+		t.Errorf("Cached client performed %d requests; want 2", n)
+	}
+
+	// Served from the cache populated by Load
+	ps, err := c.LoadMany(ctx, "NERGALIC", "Nergalic")
+	if !reflect.DeepEqual(ps, []*Profile{exp}) || err != nil {
+		t.Errorf("LoadMany(ctx, \"NERGALIC\", \"Nergalic\") was %v, %s; want %v, <nil>", ps, err, []*Profile{exp})
+	}
+	if n := rt.Count(); n != 2 {
+		t.Errorf("LoadMany(ctx, \"NERGALIC\", \"Nergalic\") performed a request despite the cache")
+	}
+}
+
+// Test that forced loads aren't served from the cache, but update it.
+func TestClientCacheForce(t *testing.T) {
+	t.Parallel()
+
+	rt := &countingTransport{transport: http.NewFileTransport(http.Dir("testdata"))}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: rt},
+		Cache:      NewMemoryCache(0),
+	}
+	c.HistorySource = LegacyNameHistory{Client: c}
+	ctx := context.Background()
+	id := ID("087cc153c3434ff7ac497de1569affa1")
+
+	loads := [...]struct {
+		force    bool
+		expCount int
+	}{
+		{force: false, expCount: 1},
+		{force: false, expCount: 1},
+		{force: true, expCount: 2},
+		{force: false, expCount: 2},
+	}
+	for _, l := range loads {
+		if _, err := c.LoadProperties(ctx, &Profile{ID: id}, l.force); err != nil {
+			t.Fatalf("LoadProperties(ctx, {ID: %q}, %t) failed: %s", id, l.force, err)
+		}
+		if n := rt.Count(); n != l.expCount {
+			t.Errorf("LoadProperties(ctx, {ID: %q}, %t) brought the request count to %d; want %d", id, l.force, n, l.expCount)
+		}
+	}
+	for _, l := range loads {
+		if _, err := c.LoadNameHistory(ctx, &Profile{ID: id}, l.force); err != nil {
+			t.Fatalf("LoadNameHistory(ctx, {ID: %q}, %t) failed: %s", id, l.force, err)
+		}
+		if n := rt.Count(); n != 2+l.expCount {
+			t.Errorf("LoadNameHistory(ctx, {ID: %q}, %t) brought the request count to %d; want %d", id, l.force, n, 2+l.expCount)
+		}
+	}
+}
+
+func TestClientCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	cache := &ttlStoreCache{ttls: make(map[string]time.Duration)}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: http.NewFileTransport(http.Dir("testdata"))},
+		Cache:      cache,
+	}
+	ctx := context.Background()
+	c.Load(ctx, "nergalic")
+	c.LoadWithProperties(ctx, "087cc153c3434ff7ac497de1569affa1")
+
+	exp := map[string]time.Duration{
+		"name:nergalic": DefaultCacheTTL,
+		"properties:087cc153c3434ff7ac497de1569affa1": DefaultPropertiesTTL,
+	}
+	if !reflect.DeepEqual(cache.ttls, exp) {
+		t.Errorf("Client cached values with TTLs %v; want %v", cache.ttls, exp)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+func expectCached(t *testing.T, c Cache, key, exp string) {
+	if v, ok := c.Get(key); !ok || string(v) != exp {
+		t.Errorf("%T.Get(%q) was %q, %t; want %q, true", c, key, v, ok, exp)
+	}
+}
+
+func expectMissing(t *testing.T, c Cache, key string) {
+	if v, ok := c.Get(key); ok {
+		t.Errorf("%T.Get(%q) was %q, true; want missing", c, key, v)
+	}
+}
+
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+type countingTransport struct {
+	mu        sync.Mutex
+	count     int
+	transport http.RoundTripper
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.mu.Lock()
+	ct.count++
+	ct.mu.Unlock()
+	return ct.transport.RoundTrip(req)
+}
+
+func (ct *countingTransport) Count() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.count
+}
+
+type ttlStoreCache struct {
+	ttls map[string]time.Duration
+}
+
+func (c *ttlStoreCache) Get(key string) ([]byte, bool) { return nil, false }
+
+func (c *ttlStoreCache) Set(key string, value []byte, ttl time.Duration) {
+	c.ttls[key] = ttl
+}
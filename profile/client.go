@@ -0,0 +1,207 @@
+package profile
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// A Client loads Minecraft profiles from the Mojang servers. Its zero value is
+// a usable client which communicates with the official Mojang servers using
+// http.DefaultClient.
+//
+// A Client is safe for concurrent use by multiple goroutines, but its fields
+// should not be modified while it is in use.
+type Client struct {
+	// HTTPClient is the HTTP client used to communicate with the Mojang
+	// servers. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Endpoints selects the endpoints used by Load, LoadMany and LoadByID to
+	// look up profiles by username and ID. The zero value selects the legacy
+	// MojangEndpoints. Errors are reported alike for either endpoint set.
+	Endpoints Endpoints
+
+	// APIURL is the base URL of the server handling lookups by username and
+	// ID using MojangEndpoints. It also handles lookups by username at a
+	// specific time, no matter the endpoints selected. If empty,
+	// DefaultAPIURL is used.
+	APIURL string
+	// ServicesURL is the base URL of the server handling lookups by username
+	// and ID using ServicesEndpoints. If empty, DefaultServicesURL is used.
+	ServicesURL string
+	// SessionURL is the base URL of the server handling profile properties
+	// requests. If empty, DefaultSessionURL is used.
+	SessionURL string
+	// AssetsURL is the base URL of the server hosting the default skin
+	// textures. If empty, DefaultAssetsURL is used.
+	AssetsURL string
+
+	// UserAgent is sent as the User-Agent header of every request.
+	// If empty, the default User-Agent of package net/http is sent.
+	UserAgent string
+
+	// Timeout is the time limit applied to operations whose context has no
+	// deadline. The limit includes reading the body of texture readers.
+	// Zero means no time limit.
+	Timeout time.Duration
+
+	// Cache, if non-nil, is consulted before profiles and profile
+	// properties are requested from the Mojang servers, and stores the
+	// responses received. Lookups at a specific time are never cached.
+	Cache Cache
+	// CacheTTL is how long profiles are cached.
+	// If zero, DefaultCacheTTL is used.
+	CacheTTL time.Duration
+	// PropertiesTTL is how long profile properties are cached.
+	// If zero, DefaultPropertiesTTL is used.
+	PropertiesTTL time.Duration
+
+	// Limiter, if non-nil, is consulted before each request to the Mojang
+	// API, preventing the client from exceeding the rate limits of the API.
+	// Cached responses and texture downloads are not rate limited.
+	Limiter *Limiter
+
+	// Retry, if non-nil, determines how requests which fail with a 429 Too
+	// Many Requests or 5xx server error response are retried. If nil,
+	// failed requests are not retried.
+	Retry *RetryPolicy
+
+	// BatchConcurrency is the maximum number of batches LoadAll loads
+	// concurrently. If zero, DefaultBatchConcurrency is used.
+	BatchConcurrency int
+
+	// SignedProperties makes the client request profile properties along
+	// with their signatures, keeping them in Properties.Raw so they can be
+	// verified using Verify.
+	SignedProperties bool
+	// PublicKey is the key property signatures are verified against.
+	// If nil, YggdrasilPublicKey is used.
+	PublicKey *rsa.PublicKey
+
+	// HistorySource provides the name histories loaded by LoadNameHistory
+	// and LoadWithNameHistory. If nil, loading name histories fails with
+	// ErrNameHistoryUnavailable.
+	HistorySource NameHistorySource
+
+	// Observer, if non-nil, is notified of the ID and username of every
+	// profile received from the Mojang servers by Load, LoadAtTime,
+	// LoadByID, LoadMany and LoadProperties, and the functions built upon
+	// them. Responses served from Cache are not observed again.
+	Observer NameObserver
+
+	// LenientUsernames makes the client accept legacy usernames which don't
+	// follow the current username rules. See ValidateUsername.
+	LenientUsernames bool
+}
+
+// RetryPolicy determines how a Client retries requests which fail with a 429
+// Too Many Requests or 5xx server error response. It is shared with the other
+// packages of this module.
+type RetryPolicy = internal.RetryPolicy
+
+// DefaultRetryPolicy is the RetryPolicy of DefaultClient.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxRetries: internal.DefaultRetryPolicy.MaxRetries,
+	MinBackoff: internal.DefaultRetryPolicy.MinBackoff,
+	MaxBackoff: internal.DefaultRetryPolicy.MaxBackoff,
+	Jitter:     internal.DefaultRetryPolicy.Jitter,
+}
+
+// DefaultClient is the Client used by the package-level functions and by the
+// methods of Profile and Properties.
+var DefaultClient = &Client{
+	Timeout: DefaultTimeout,
+	Retry:   DefaultRetryPolicy,
+}
+
+func (c *Client) apiURL(format string, a ...interface{}) string {
+	return baseURL(c.APIURL, DefaultAPIURL) + fmt.Sprintf(format, a...)
+}
+
+// lookupURL returns the URL of a profile lookup endpoint, formatting
+// mojangPath or servicesPath depending on the endpoints selected by c.
+func (c *Client) lookupURL(mojangPath, servicesPath string, a ...interface{}) string {
+	if c.Endpoints == ServicesEndpoints {
+		return baseURL(c.ServicesURL, DefaultServicesURL) + fmt.Sprintf(servicesPath, a...)
+	}
+	return c.apiURL(mojangPath, a...)
+}
+
+func (c *Client) sessionURL(format string, a ...interface{}) string {
+	return baseURL(c.SessionURL, DefaultSessionURL) + fmt.Sprintf(format, a...)
+}
+
+func (c *Client) assetsURL(path string) string {
+	return baseURL(c.AssetsURL, DefaultAssetsURL) + path
+}
+
+func baseURL(configured, fallback string) string {
+	if configured == "" {
+		return fallback
+	}
+	return configured
+}
+
+// server returns the internal client used to perform requests which are not
+// rate limited.
+func (c *Client) server() *internal.Client {
+	s := &internal.Client{
+		HTTPClient: c.HTTPClient,
+		UserAgent:  c.UserAgent,
+		Retry:      c.Retry,
+	}
+	return s
+}
+
+// apiServer returns the internal client used to perform load requests, which
+// are subject to the shared rate limit. Retries count against it too.
+func (c *Client) apiServer() *internal.Client {
+	s := c.server()
+	if l := c.Limiter; l != nil {
+		s.Wait = func(ctx context.Context, retry int) error { return l.wait(ctx, "") }
+	}
+	return s
+}
+
+// sessionServer returns the internal client used to request the properties
+// of the profile identified by id, which are subject to a per-profile limit.
+// Only the first attempt of a request starts the cooldown of the profile, as
+// retries of failed requests would be stopped by it.
+func (c *Client) sessionServer(id ID) *internal.Client {
+	s := c.server()
+	if l := c.Limiter; l != nil {
+		s.Wait = func(ctx context.Context, retry int) error {
+			if retry > 0 {
+				return nil
+			}
+			return l.wait(ctx, string(id))
+		}
+	}
+	return s
+}
+
+// context applies c.Timeout to ctx if ctx has no deadline.
+// The returned CancelFunc must be called when the operation completes.
+func (c *Client) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
+
+// cancelOnClose cancels the context of a response body when it is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r cancelOnClose) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,293 @@
+package profile
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+func TestClientUserAgent(t *testing.T) {
+	t.Parallel()
+
+	rt := &reqStoreTransport{}
+	c := &Client{HTTPClient: &http.Client{Transport: rt}, UserAgent: "tester/1.0"}
+	c.Load(context.Background(), "nergalic")
+
+	if rt.Request == nil {
+		t.Fatal("Load(ctx, \"nergalic\") didn't perform a request")
+	}
+	if ua := rt.Request.Header.Get("User-Agent"); ua != c.UserAgent {
+		t.Errorf("Load(ctx, \"nergalic\") sent User-Agent %q; want %q", ua, c.UserAgent)
+	}
+}
+
+var testClientBaseURLInput = [...]struct {
+	client *Client
+	load   func(c *Client, ctx context.Context)
+	expURL string
+}{
+	{
+		client: &Client{APIURL: "http://api.example.com"},
+		load:   func(c *Client, ctx context.Context) { c.Load(ctx, "nergalic") },
+		expURL: "http://api.example.com/users/profiles/minecraft/nergalic",
+	},
+	{
+		client: &Client{APIURL: "http://api.example.com"},
+		load:   func(c *Client, ctx context.Context) { c.LoadMany(ctx, "nergalic") },
+		expURL: "http://api.example.com/profiles/minecraft",
+	},
+	{
+		client: &Client{APIURL: "http://api.example.com"},
+		load: func(c *Client, ctx context.Context) {
+			c.LoadByID(ctx, "087cc153c3434ff7ac497de1569affa1")
+		},
+		expURL: "http://api.example.com/user/profile/087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		client: &Client{Endpoints: ServicesEndpoints, ServicesURL: "http://services.example.com"},
+		load:   func(c *Client, ctx context.Context) { c.Load(ctx, "nergalic") },
+		expURL: "http://services.example.com/minecraft/profile/lookup/name/nergalic",
+	},
+	{
+		client: &Client{Endpoints: ServicesEndpoints},
+		load:   func(c *Client, ctx context.Context) { c.LoadMany(ctx, "nergalic") },
+		expURL: "https://api.minecraftservices.com/minecraft/profile/lookup/bulk/byname",
+	},
+	{
+		client: &Client{Endpoints: ServicesEndpoints},
+		load: func(c *Client, ctx context.Context) {
+			c.LoadByID(ctx, "087cc153c3434ff7ac497de1569affa1")
+		},
+		expURL: "https://api.minecraftservices.com/minecraft/profile/lookup/087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		client: &Client{Endpoints: ServicesEndpoints, APIURL: "http://api.example.com"},
+		load:   func(c *Client, ctx context.Context) { c.LoadAtTime(ctx, "nergalic", time.Unix(0, 0)) },
+		expURL: "http://api.example.com/users/profiles/minecraft/nergalic?at=0",
+	},
+	{
+		client: &Client{SessionURL: "http://session.example.com"},
+		load: func(c *Client, ctx context.Context) {
+			c.LoadWithProperties(ctx, "087cc153c3434ff7ac497de1569affa1")
+		},
+		expURL: "http://session.example.com/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		client: &Client{AssetsURL: "http://assets.example.com"},
+		load:   func(c *Client, ctx context.Context) { c.SkinReader(ctx, &Properties{Model: Alex}) },
+		expURL: "http://assets.example.com/SkinTemplates/alex.png",
+	},
+}
+
+func TestClientBaseURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testClientBaseURLInput {
+		rt := &reqStoreTransport{}
+		c := *tc.client
+		c.HTTPClient = &http.Client{Transport: rt}
+		tc.load(&c, context.Background())
+
+		if rt.Request == nil {
+			t.Errorf("%#v performed no request; want request to %q", tc.client, tc.expURL)
+		} else if u := rt.Request.URL.String(); u != tc.expURL {
+			t.Errorf("%#v requested %q; want %q", tc.client, u, tc.expURL)
+		}
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	t.Parallel()
+
+	rt := &reqStoreTransport{}
+	c := &Client{HTTPClient: &http.Client{Transport: rt}, Timeout: time.Minute}
+
+	before := time.Now()
+	c.Load(context.Background(), "nergalic")
+
+	if rt.Request == nil {
+		t.Fatal("Load(ctx, \"nergalic\") didn't perform a request")
+	}
+	deadline, ok := rt.Request.Context().Deadline()
+	if !ok || deadline.Before(before.Add(c.Timeout)) {
+		t.Errorf("Load(ctx, \"nergalic\") had deadline %s, %t; want one minute from now", deadline, ok)
+	}
+
+	// A deadline set by the caller must take precedence.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	c.Load(ctx, "nergalic")
+
+	if rt.Request.Context() != ctx {
+		t.Error("Load(ctx, \"nergalic\") replaced the deadline of ctx")
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	t.Parallel()
+
+	rt := &failFirstTransport{
+		status:    429,
+		transport: http.NewFileTransport(http.Dir("testdata")),
+	}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: rt},
+		Retry:      &RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond},
+	}
+
+	exp := &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}
+	if p, err := c.Load(context.Background(), "nergalic"); !reflect.DeepEqual(p, exp) || err != nil {
+		t.Errorf("Load(ctx, \"nergalic\") after 429 was %#v, %s; want %#v, <nil>", p, err, exp)
+	}
+
+	c.Retry = nil
+	rt.failed = false
+	if _, err := c.Load(context.Background(), "nergalic"); err != ErrTooManyRequests {
+		t.Errorf("Load(ctx, \"nergalic\") without retry policy returned %s; want %s", p(err), ErrTooManyRequests)
+	}
+}
+
+func TestClientServicesEndpoints(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{
+		HTTPClient: &http.Client{Transport: http.NewFileTransport(http.Dir("testdata"))},
+		Endpoints:  ServicesEndpoints,
+	}
+	ctx := context.Background()
+	nergalic := &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}
+
+	if pr, err := c.Load(ctx, "nergalic"); !reflect.DeepEqual(pr, nergalic) || err != nil {
+		t.Errorf("Load(ctx, \"nergalic\") was %#v, %s; want %#v, <nil>", pr, p(err), nergalic)
+	}
+	if pr, err := c.LoadByID(ctx, string(nergalic.ID)); !reflect.DeepEqual(pr, nergalic) || err != nil {
+		t.Errorf("LoadByID(ctx, %q) was %#v, %s; want %#v, <nil>", nergalic.ID, pr, p(err), nergalic)
+	}
+	exp := []*Profile{{ID: "d9a5b542ce88442aaab38ec13e6c7773", Name: "BreeSakana"}, nergalic}
+	if ps, err := c.LoadMany(ctx, "breesakana", "nergalic"); !reflect.DeepEqual(ps, exp) || err != nil {
+		t.Errorf("LoadMany(ctx, \"breesakana\", \"nergalic\") was %v, %s; want %v, <nil>", ps, p(err), exp)
+	}
+}
+
+var testClientServicesErrorsInput = [...]struct {
+	status int
+	body   string
+	expErr error
+}{
+	{404, `{"path":"/minecraft/profile/lookup/name/doesNotExist","errorMessage":"Couldn't find any profile with name doesNotExist"}`, ErrNoSuchProfile},
+	{204, ``, ErrNoSuchProfile},
+	{429, `{"path":"/minecraft/profile/lookup/name/doesNotExist","errorMessage":"Too many requests"}`, ErrTooManyRequests},
+	{429, ``, ErrTooManyRequests},
+}
+
+func TestClientServicesErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testClientServicesErrorsInput {
+		c := &Client{
+			HTTPClient: &http.Client{Transport: &staticTransport{status: tc.status, body: tc.body}},
+			Endpoints:  ServicesEndpoints,
+		}
+		if _, err := c.Load(context.Background(), "doesNotExist"); err != tc.expErr {
+			t.Errorf("Load(ctx, \"doesNotExist\") on %d response %q returned %s; want %s", tc.status, tc.body, p(err), tc.expErr)
+		}
+		if _, err := c.LoadByID(context.Background(), "00000000000000000000000000000000"); err != tc.expErr {
+			t.Errorf("LoadByID(ctx, \"00000000000000000000000000000000\") on %d response %q returned %s; want %s", tc.status, tc.body, p(err), tc.expErr)
+		}
+	}
+}
+
+// Test that 404 responses only mean that no profile exists when looking up
+// profiles using ServicesEndpoints.
+func TestClientMojangNotFound(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{HTTPClient: &http.Client{Transport: &staticTransport{status: 404}}}
+	ctx := context.Background()
+	exp := &internal.FailedRequestError{StatusCode: 404}
+	is404 := func(err error) bool {
+		e, ok := internal.UnwrapFailedRequestError(err)
+		return ok && e.StatusCode == 404
+	}
+
+	if _, err := c.Load(ctx, "doesNotExist"); !is404(err) {
+		t.Errorf("Load(ctx, \"doesNotExist\") on 404 response returned %s; want %s", p(err), exp)
+	}
+	if _, err := c.LoadByID(ctx, "00000000000000000000000000000000"); !is404(err) {
+		t.Errorf("LoadByID(ctx, \"00000000000000000000000000000000\") on 404 response returned %s; want %s", p(err), exp)
+	}
+
+	c.Endpoints = ServicesEndpoints
+	if _, err := c.LoadAtTime(ctx, "doesNotExist", time.Unix(0, 0)); !is404(err) {
+		t.Errorf("LoadAtTime(ctx, \"doesNotExist\", 1970) on 404 response returned %s; want %s", p(err), exp)
+	}
+}
+
+func TestEndpointsString(t *testing.T) {
+	for _, tc := range []struct {
+		endpoints Endpoints
+		expStr    string
+	}{
+		{MojangEndpoints, "Mojang"},
+		{ServicesEndpoints, "Minecraft Services"},
+		{Endpoints(9), "???"},
+	} {
+		if s := tc.endpoints.String(); s != tc.expStr {
+			t.Errorf("Endpoints(%d).String() was %q; want %q", byte(tc.endpoints), s, tc.expStr)
+		}
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+type reqStoreTransport struct {
+	Request *http.Request
+}
+
+func (rt *reqStoreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.Request = req
+	return nil, testError
+}
+
+// failFirstTransport fails the first request with the given status.
+type failFirstTransport struct {
+	status    int
+	failed    bool
+	transport http.RoundTripper
+}
+
+func (ft *failFirstTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !ft.failed {
+		ft.failed = true
+		return &http.Response{
+			StatusCode: ft.status,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader(`{"error":"TooManyRequestsException"}`)),
+			Request:    req,
+		}, nil
+	}
+	return ft.transport.RoundTrip(req)
+}
+
+// staticTransport responds to every request with the given status and body.
+type staticTransport struct {
+	status int
+	body   string
+}
+
+func (st *staticTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: st.status,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader(st.body)),
+		Request:    req,
+	}, nil
+}
@@ -1,12 +1,59 @@
 package profile
 
+import "time"
+
+// Base URLs of the Mojang servers used unless a Client is configured otherwise.
+const (
+	DefaultAPIURL      = "https://api.mojang.com"
+	DefaultServicesURL = "https://api.minecraftservices.com"
+	DefaultSessionURL  = "https://sessionserver.mojang.com"
+	DefaultAssetsURL   = "http://assets.mojang.com"
+)
+
+// Endpoints identifies a set of endpoints which profiles can be looked up
+// from by username and ID.
+type Endpoints byte
+
+const (
+	MojangEndpoints   Endpoints = iota // The legacy endpoints of api.mojang.com.
+	ServicesEndpoints                  // The endpoints of api.minecraftservices.com.
+)
+
+// String returns a string representation of e.
+//
+//	MojangEndpoints.String()   = "Mojang"
+//	ServicesEndpoints.String() = "Minecraft Services"
+//
+// String returns "???" for endpoint sets not declared by this package.
+func (e Endpoints) String() string {
+	switch e {
+	case MojangEndpoints:
+		return "Mojang"
+	case ServicesEndpoints:
+		return "Minecraft Services"
+	default:
+		return "???"
+	}
+}
+
+// DefaultTimeout is the time limit DefaultClient applies to operations whose
+// context has no deadline.
+const DefaultTimeout = 30 * time.Second
+
+// Endpoint paths relative to the base URLs above.
 const (
-	loadURL                = "https://api.mojang.com/users/profiles/minecraft/%s"
-	loadAtTimeURL          = "https://api.mojang.com/users/profiles/minecraft/%s?at=%d"
-	loadWithNameHistoryURL = "https://api.mojang.com/user/profiles/%s/names"
-	loadWithPropertiesURL  = "https://sessionserver.mojang.com/session/minecraft/profile/%s"
-	loadManyURL            = "https://api.mojang.com/profiles/minecraft"
-
-	steveSkinURL = "http://assets.mojang.com/SkinTemplates/steve.png"
-	alexSkinURL  = "http://assets.mojang.com/SkinTemplates/alex.png"
+	loadPath                 = "/users/profiles/minecraft/%s"
+	loadAtTimePath           = "/users/profiles/minecraft/%s?at=%d"
+	loadByIDPath             = "/user/profile/%s"
+	loadWithNameHistoryPath  = "/user/profiles/%s/names"
+	loadWithPropertiesPath   = "/session/minecraft/profile/%s"
+	loadSignedPropertiesPath = "/session/minecraft/profile/%s?unsigned=false"
+	loadManyPath             = "/profiles/minecraft"
+
+	servicesLoadPath     = "/minecraft/profile/lookup/name/%s"
+	servicesLoadByIDPath = "/minecraft/profile/lookup/%s"
+	servicesLoadManyPath = "/minecraft/profile/lookup/bulk/byname"
+
+	steveSkinPath = "/SkinTemplates/steve.png"
+	alexSkinPath  = "/SkinTemplates/alex.png"
 )
@@ -0,0 +1,141 @@
+//go:build !integration
+// +build !integration
+
+package profile
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// The tests below run the table entries which DefaultClient can serve through
+// the package-level functions, checking that these use DefaultClient.
+
+func TestLoad_DefaultClient(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testLoadInput {
+		if !servedByDefaultClient(tc.transport) {
+			continue
+		}
+		profile, err := Load(context.Background(), tc.username)
+		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"Load(ctx, %q)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.username,
+				profile, p(err),
+				tc.expProfile, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestLoadAtTime_DefaultClient(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testLoadAtTimeInput {
+		if !servedByDefaultClient(tc.transport) {
+			continue
+		}
+		profile, err := LoadAtTime(context.Background(), tc.username, tc.time)
+		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"LoadAtTime(ctx, %q, %s)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.username, tc.time,
+				profile, p(err),
+				tc.expProfile, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestLoadByID_DefaultClient(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testLoadByIDInput {
+		if !servedByDefaultClient(tc.transport) {
+			continue
+		}
+		profile, err := LoadByID(context.Background(), tc.id)
+		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"LoadByID(ctx, %q)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.id,
+				profile, p(err),
+				tc.expProfile, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestLoadWithProperties_DefaultClient(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testLoadWithPropertiesInput {
+		if !servedByDefaultClient(tc.transport) {
+			continue
+		}
+		profile, err := LoadWithProperties(context.Background(), tc.id)
+		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"LoadWithProperties(ctx, %q)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.id,
+				profile, p(err),
+				tc.expProfile, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestLoadMany_DefaultClient(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testLoadManyInput {
+		if !servedByDefaultClient(tc.transport) {
+			continue
+		}
+		profiles, err := LoadMany(context.Background(), tc.ids...)
+		if !reflect.DeepEqual(profiles, tc.expProfiles) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"LoadMany(ctx, %q)\n"+
+					" was: %s, %s\n"+
+					"want: %s, %s",
+				tc.ids,
+				profiles, p(err),
+				tc.expProfiles, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestProfile_LoadProperties_DefaultClient(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testProfileLoadPropertiesInput {
+		if !servedByDefaultClient(tc.transport) {
+			continue
+		}
+		profile := *tc.profile
+
+		props, err := profile.LoadProperties(context.Background(), tc.force)
+		if !reflect.DeepEqual(&profile, tc.expProfile) || !reflect.DeepEqual(props, tc.expProps) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"%#v.LoadProperties(ctx, %t) produced result:\n"+
+					"      %#v, %#v, %s\n"+
+					"want: %#v, %#v, %s",
+				tc.profile, tc.force,
+				&profile, props, p(err),
+				tc.expProfile, tc.expProps, p(tc.expErr),
+			)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package profile
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -11,6 +12,15 @@ var (
 	ErrUnsetPlayerID = errors.New("minecraft/profile: player id is not set")
 	ErrUnknownModel  = errors.New("minecraft/profile: unknown model")
 
+	// ErrNameHistoryUnavailable is returned when the name history of a
+	// profile is requested, but isn't available. Mojang no longer serves
+	// name histories, so a Client must be configured with a
+	// NameHistorySource to load them.
+	ErrNameHistoryUnavailable = errors.New("minecraft/profile: name history is unavailable")
+
+	ErrUnsignedProperties = errors.New("minecraft/profile: properties are not signed")
+	ErrNoPublicKey        = errors.New("minecraft/profile: no public key to verify signatures against")
+
 	// ErrTooManyRequests is returned if the client has exceeded its server
 	// communication rate limit. At the time of writing, the load operations
 	// have a shared rate limit of 600 requests per 10 minutes.
@@ -18,6 +28,9 @@ var (
 	// Note that the rate limit for reading profile properties is much
 	// stricter: For each profile, profile properties may only be requested
 	// once per minute.
+	//
+	// A Client can be configured with a Limiter to avoid exceeding the rate
+	// limits in the first place.
 	ErrTooManyRequests = errors.New("minecraft/profile: request rate limit exceeded")
 )
 
@@ -30,3 +43,56 @@ type ErrMaxSizeExceeded struct {
 func (e ErrMaxSizeExceeded) Error() string {
 	return fmt.Sprintf("minecraft/profile: aggregate request size of %d exceeded maximum of %d", e.Size, LoadManyMaxSize)
 }
+
+// A RateLimitError is returned by a Client with a non-blocking Limiter when a
+// request would exceed Mojang's rate limits if performed. A blocking Limiter
+// returns it when the request's context deadline is too near for waiting.
+type RateLimitError struct {
+	Wait time.Duration // Time until the request may be performed.
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("minecraft/profile: request rate limit would be exceeded; next request allowed in %s", e.Wait)
+}
+
+// An InvalidIDError is returned when a malformed profile ID is encountered.
+type InvalidIDError struct {
+	ID string // The malformed ID.
+}
+
+func (e *InvalidIDError) Error() string {
+	return fmt.Sprintf("minecraft/profile: invalid profile id %q", e.ID)
+}
+
+// An InvalidUsernameError is returned when a malformed username is requested
+// loaded. See ValidateUsername.
+type InvalidUsernameError struct {
+	Username string // The malformed username.
+}
+
+func (e *InvalidUsernameError) Error() string {
+	return fmt.Sprintf("minecraft/profile: invalid username %q", e.Username)
+}
+
+// A SignatureError is returned by Verify when a property's signature is
+// invalid, i.e. if the property hasn't been signed by the expected key.
+type SignatureError struct {
+	Property string // Name of the property.
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("minecraft/profile: invalid signature of property %q", e.Property)
+}
+
+// An InvalidTextureError is returned when a texture image is malformed.
+type InvalidTextureError struct {
+	URL    string // URL of the texture, if retrieved from one.
+	Reason string // Description of what is wrong with the texture.
+}
+
+func (e *InvalidTextureError) Error() string {
+	if e.URL == "" {
+		return "minecraft/profile: invalid texture: " + e.Reason
+	}
+	return fmt.Sprintf("minecraft/profile: invalid texture %s: %s", e.URL, e.Reason)
+}
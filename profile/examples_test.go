@@ -28,9 +28,10 @@ func Example() {
 	// Get case-corrected username and ID
 	name, id := p.Name, p.ID
 
-	// Load previously associated usernames
+	// Load previously associated usernames, if known. Mojang no longer
+	// serves name histories, so DefaultClient has none available.
 	hist, err := p.LoadNameHistory(ctx, false)
-	if err != nil {
+	if err != nil && err != profile.ErrNameHistoryUnavailable {
 		log.Fatalf("Failed to load profile name history: %s", err)
 	}
 
@@ -68,7 +69,7 @@ func Example() {
 	// ---------------------------------------------------------
 	// CASE-CORRECTED USERNAME:                         Nergalic
 	// ID:                      087cc153c3434ff7ac497de1569affa1
-	// PRIOR NAMES:                              [GeneralSezuan]
+	// PRIOR NAMES:                                           []
 	//
 	// SKIN MODEL:                                         Steve
 	// SKIN URL:                http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e
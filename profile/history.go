@@ -0,0 +1,289 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// A NameHistorySource provides the name histories of profiles. Mojang no
+// longer serves name histories, so a Client only can load them if
+// configured with a NameHistorySource.
+type NameHistorySource interface {
+	// NameHistory returns the current username of the profile identified by
+	// id along with its past usernames, ordered like Profile.NameHistory.
+	// name is empty if the current username isn't known by the source. If
+	// the source has no history of the profile, ErrNameHistoryUnavailable
+	// is returned.
+	NameHistory(ctx context.Context, id ID) (name string, hist []PastName, err error)
+}
+
+// LegacyNameHistory is a NameHistorySource which loads name histories from
+// the name history endpoint Mojang retired in September 2022, relative to
+// the APIURL of Client. It is only of use with servers which still implement
+// the endpoint, such as API mirrors and test servers.
+type LegacyNameHistory struct {
+	// Client is the client used to request name histories, subject to its
+	// Cache, Limiter and Retry policy. If nil, DefaultClient is used.
+	Client *Client
+}
+
+// NameHistory loads the name history of the profile identified by id.
+// If the server responds that the endpoint isn't found,
+// ErrNameHistoryUnavailable is returned.
+func (s LegacyNameHistory) NameHistory(ctx context.Context, id ID) (name string, hist []PastName, err error) {
+	return s.nameHistory(ctx, id, false)
+}
+
+// nameHistory is like NameHistory, except the name history isn't read from
+// the cache of the client if force is true.
+func (s LegacyNameHistory) nameHistory(ctx context.Context, id ID, force bool) (name string, hist []PastName, err error) {
+	c := s.Client
+	if c == nil {
+		c = DefaultClient
+	}
+
+	key := historyKey(id)
+	endpoint := c.apiURL(loadWithNameHistoryPath, url.PathEscape(string(id)))
+
+	var js json.RawMessage
+	var hit bool
+	if !force {
+		js, hit = c.cached(key)
+	}
+	if !hit {
+		ctx, cancel := c.context(ctx)
+		defer cancel()
+
+		js, err = c.apiServer().FetchJSON(ctx, endpoint)
+		if err != nil {
+			if e, ok := internal.UnwrapFailedRequestError(err); ok && (e.StatusCode == 404 || e.StatusCode == 410) {
+				return "", nil, ErrNameHistoryUnavailable
+			}
+			return "", nil, transformError(err)
+		}
+	}
+
+	var arr []pastNameJSON
+	if err = internal.Decode(js, &arr); err == nil {
+		name, hist, err = buildHistory(arr)
+	}
+	if err != nil {
+		return "", nil, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+	}
+	if !hit {
+		c.cache(key, js, c.cacheTTL())
+	}
+	return name, hist, nil
+}
+
+// A forcedHistorySource is a NameHistorySource which can be told not to
+// serve a name history from a cache, as when Client.LoadNameHistory is
+// forced.
+type forcedHistorySource interface {
+	nameHistory(ctx context.Context, id ID, force bool) (name string, hist []PastName, err error)
+}
+
+// A NameObserver is notified of the usernames of profiles as they are
+// received from the Mojang servers. Implementations must be safe for
+// concurrent use by multiple goroutines.
+type NameObserver interface {
+	// ObserveName records that the profile identified by id was named name
+	// at the time seen.
+	ObserveName(id ID, name string, seen time.Time)
+}
+
+// observe notifies c.Observer, if any, that p was received from the Mojang
+// servers.
+func (c *Client) observe(p *Profile) {
+	if c.Observer != nil {
+		c.Observer.ObserveName(p.ID, p.Name, time.Now())
+	}
+}
+
+// A NameObservation is a username observed to be used by a profile between
+// FirstSeen and LastSeen. Like PastName, do not use == with NameObservation
+// values.
+type NameObservation struct {
+	Name      string
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// HistoryStore records the usernames of profiles as they are observed,
+// keeping a file per profile in a directory. It is both a NameObserver and a
+// NameHistorySource, so a Client configured with a HistoryStore as both its
+// Observer and HistorySource builds its own name histories over time:
+//
+//	s, err := profile.NewHistoryStore(dir)
+//	...
+//	c := &profile.Client{Observer: s, HistorySource: s}
+//
+// Name histories are approximate, as a name change is only known to have
+// happened between the last observation of the old username and the first
+// observation of the new one. Failures to read or write files are treated as
+// no names having been observed.
+//
+// A HistoryStore is safe for concurrent use by multiple goroutines.
+type HistoryStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewHistoryStore returns a HistoryStore storing its observations in dir.
+// The directory is created if it doesn't exist.
+func NewHistoryStore(dir string) (*HistoryStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &HistoryStore{dir: dir}, nil
+}
+
+// ObserveName records that the profile identified by id was named name at
+// the time seen. Observations may be recorded in any order. An observation
+// made within the period another username was observed in splits that
+// period, keeping only the first and last time the other username was seen
+// on either side. Observations contradicting another made at the same
+// instant are ignored.
+//
+// Each observation which changes what is known about a profile rewrites the
+// profile's file as a whole. The files are small, holding one entry per
+// username change, but callers observing many names in bulk should expect a
+// file write per changed profile. Observing a username already known to be
+// used at the time seen writes nothing.
+func (s *HistoryStore) ObserveName(id ID, name string, seen time.Time) {
+	id, err := ParseID(string(id))
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if obs, changed := addObservation(s.read(id), name, seen); changed {
+		s.write(id, obs)
+	}
+}
+
+// addObservation adds the observation of name at the time seen to obs, which
+// must be ordered by FirstSeen without overlapping periods. It reports
+// whether obs was changed.
+func addObservation(obs []NameObservation, name string, seen time.Time) ([]NameObservation, bool) {
+	// Find the first observation which begins after seen.
+	i := sort.Search(len(obs), func(i int) bool { return obs[i].FirstSeen.After(seen) })
+
+	if i > 0 && !seen.After(obs[i-1].LastSeen) { // Within the period of obs[i-1]
+		o := obs[i-1]
+		if o.Name == name || seen.Equal(o.FirstSeen) || seen.Equal(o.LastSeen) {
+			return obs, false
+		}
+		obs = append(obs, NameObservation{}, NameObservation{})
+		copy(obs[i+2:], obs[i:])
+		obs[i-1] = NameObservation{Name: o.Name, FirstSeen: o.FirstSeen, LastSeen: o.FirstSeen}
+		obs[i] = NameObservation{Name: name, FirstSeen: seen, LastSeen: seen}
+		obs[i+1] = NameObservation{Name: o.Name, FirstSeen: o.LastSeen, LastSeen: o.LastSeen}
+		return obs, true
+	}
+
+	// Between the periods of obs[i-1] and obs[i]; extend or merge them if
+	// they have the same username.
+	prev := i > 0 && obs[i-1].Name == name
+	next := i < len(obs) && obs[i].Name == name
+	switch {
+	case prev && next:
+		obs[i-1].LastSeen = obs[i].LastSeen
+		obs = append(obs[:i], obs[i+1:]...)
+	case prev:
+		obs[i-1].LastSeen = seen
+	case next:
+		obs[i].FirstSeen = seen
+	default:
+		obs = append(obs, NameObservation{})
+		copy(obs[i+1:], obs[i:])
+		obs[i] = NameObservation{Name: name, FirstSeen: seen, LastSeen: seen}
+	}
+	return obs, true
+}
+
+// Observations returns the usernames observed for the profile identified by
+// id, ordered by when they first were seen.
+func (s *HistoryStore) Observations(id ID) []NameObservation {
+	id, err := ParseID(string(id))
+	if err != nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(id)
+}
+
+// NameHistory returns the last username observed for the profile identified
+// by id along with the usernames observed before it, ordered like
+// Profile.NameHistory. Each past username is considered used until the
+// following username first was seen. If no usernames have been observed for
+// the profile, ErrNameHistoryUnavailable is returned.
+func (s *HistoryStore) NameHistory(ctx context.Context, id ID) (name string, hist []PastName, err error) {
+	obs := s.Observations(id)
+	if len(obs) == 0 {
+		return "", nil, ErrNameHistoryUnavailable
+	}
+
+	last := len(obs) - 1
+	hist = make([]PastName, last)
+	for i, o := range obs[:last] {
+		hist[last-1-i] = PastName{Name: o.Name, Until: obs[i+1].FirstSeen}
+	}
+	return obs[last].Name, hist, nil
+}
+
+// storedObservation is the JSON representation of a NameObservation, with
+// times given in milliseconds since the Unix epoch like in Mojang's name
+// histories.
+type storedObservation struct {
+	Name      string `json:"name"`
+	FirstSeen int64  `json:"firstSeen"`
+	LastSeen  int64  `json:"lastSeen"`
+}
+
+// read returns the observations stored for id. s.mu must be held.
+func (s *HistoryStore) read(id ID) []NameObservation {
+	bs, err := ioutil.ReadFile(s.path(id))
+	if err != nil {
+		return nil
+	}
+	var stored []storedObservation
+	if json.Unmarshal(bs, &stored) != nil {
+		return nil
+	}
+	obs := make([]NameObservation, len(stored))
+	for i, o := range stored {
+		obs[i] = NameObservation{Name: o.Name, FirstSeen: msToTime(o.FirstSeen), LastSeen: msToTime(o.LastSeen)}
+	}
+	return obs
+}
+
+// write replaces the observations stored for id. s.mu must be held.
+func (s *HistoryStore) write(id ID, obs []NameObservation) {
+	stored := make([]storedObservation, len(obs))
+	for i, o := range obs {
+		stored[i] = storedObservation{Name: o.Name, FirstSeen: timeToMs(o.FirstSeen), LastSeen: timeToMs(o.LastSeen)}
+	}
+	if bs, err := json.Marshal(stored); err == nil {
+		writeFileAtomic(s.path(id), bs)
+	}
+}
+
+// path returns the name of the file holding the observations of the profile
+// identified by id.
+func (s *HistoryStore) path(id ID) string {
+	return filepath.Join(s.dir, string(id)+".json")
+}
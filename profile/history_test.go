@@ -0,0 +1,327 @@
+package profile
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadNameHistoryWithoutSource(t *testing.T) {
+	t.Parallel()
+
+	hist := []PastName{{Name: "NotReplaced"}}
+	c := &Client{HTTPClient: &http.Client{Transport: errorTransport{testError}}}
+	pr := &Profile{ID: "087cc153c3434ff7ac497de1569affa1", NameHistory: hist}
+
+	if h, err := c.LoadNameHistory(context.Background(), pr, true); !reflect.DeepEqual(h, hist) || err != ErrNameHistoryUnavailable {
+		t.Errorf("LoadNameHistory(ctx, %v, true) without history source was %v, %s; want %v, %s", pr, h, p(err), hist, ErrNameHistoryUnavailable)
+	}
+}
+
+func TestLoadNameHistoryCustomSource(t *testing.T) {
+	t.Parallel()
+
+	src := &fakeHistorySource{name: "Nergalic"} // No past names
+	c := &Client{HistorySource: src}
+	pr := &Profile{ID: "087CC153-C343-4FF7-AC49-7DE1569AFFA1", Name: "GeneralSezuan"}
+
+	h, err := c.LoadNameHistory(context.Background(), pr, false)
+	if h == nil || len(h) != 0 || err != nil {
+		t.Errorf("LoadNameHistory(ctx, %v, false) was %#v, %s; want []PastName{}, <nil>", pr, h, p(err))
+	}
+	if pr.Name != "Nergalic" {
+		t.Errorf("LoadNameHistory(ctx, %v, false) set name %q; want \"Nergalic\"", pr, pr.Name)
+	}
+	if src.id != "087cc153c3434ff7ac497de1569affa1" {
+		t.Errorf("LoadNameHistory(ctx, %v, false) requested history of %q; want \"087cc153c3434ff7ac497de1569affa1\"", pr, src.id)
+	}
+
+	src.err = ErrNameHistoryUnavailable
+	if _, err := c.LoadNameHistory(context.Background(), pr, true); err != ErrNameHistoryUnavailable {
+		t.Errorf("LoadNameHistory(ctx, %v, true) returned %s; want %s", pr, p(err), ErrNameHistoryUnavailable)
+	}
+}
+
+func TestLegacyNameHistoryRetired(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []int{404, 410} {
+		c := &Client{HTTPClient: &http.Client{
+			Transport: statusOverrideTransport{status: status, transport: http.NewFileTransport(http.Dir("testdata"))},
+		}}
+		name, hist, err := LegacyNameHistory{Client: c}.NameHistory(context.Background(), "087cc153c3434ff7ac497de1569affa1")
+		if name != "" || hist != nil || err != ErrNameHistoryUnavailable {
+			t.Errorf("LegacyNameHistory.NameHistory(ctx, id) on status %d was %q, %v, %s; want \"\", nil, %s", status, name, hist, p(err), ErrNameHistoryUnavailable)
+		}
+	}
+}
+
+func TestHistoryStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const id = "087cc153c3434ff7ac497de1569affa1"
+	s, err := NewHistoryStore(dir)
+	if err != nil {
+		t.Fatalf("NewHistoryStore(%q) failed: %s", dir, err)
+	}
+
+	s.ObserveName(id, "GeneralSezuan", msToTime(1000))
+	s.ObserveName(id, "Nergalic", msToTime(5000))
+	s.ObserveName(id, "GeneralSezuan", msToTime(2000))
+	s.ObserveName(id, "Nergalic", msToTime(4000)) // Out of order
+	s.ObserveName(id, "Nergalic", msToTime(6000))
+	s.ObserveName("087CC153-C343-4FF7-AC49-7DE1569AFFA1", "Nergalic", msToTime(7000))
+	s.ObserveName("../escape", "Invalid", msToTime(7000)) // Ignored
+
+	// Observations must survive across instances
+	s, _ = NewHistoryStore(dir)
+
+	expObs := []NameObservation{
+		{Name: "GeneralSezuan", FirstSeen: msToTime(1000), LastSeen: msToTime(2000)},
+		{Name: "Nergalic", FirstSeen: msToTime(4000), LastSeen: msToTime(7000)},
+	}
+	if obs := s.Observations(id); !reflect.DeepEqual(obs, expObs) {
+		t.Errorf("Observations(%q) was %v; want %v", id, obs, expObs)
+	}
+
+	expHist := []PastName{{Name: "GeneralSezuan", Until: msToTime(4000)}}
+	name, hist, err := s.NameHistory(context.Background(), id)
+	if name != "Nergalic" || !reflect.DeepEqual(hist, expHist) || err != nil {
+		t.Errorf("NameHistory(ctx, %q) was %q, %v, %s; want \"Nergalic\", %v, <nil>", id, name, hist, p(err), expHist)
+	}
+
+	const unknown = "00000000000000000000000000000000"
+	if name, hist, err := s.NameHistory(context.Background(), unknown); name != "" || hist != nil || err != ErrNameHistoryUnavailable {
+		t.Errorf("NameHistory(ctx, %q) was %q, %v, %s; want \"\", nil, %s", unknown, name, hist, p(err), ErrNameHistoryUnavailable)
+	}
+}
+
+func TestHistoryStoreOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const id = "087cc153c3434ff7ac497de1569affa1"
+	s, _ := NewHistoryStore(dir)
+	for i, name := range []string{"first", "second", "first", "current"} {
+		s.ObserveName(id, name, msToTime(int64(i+1)*1000))
+	}
+
+	// Like buildHistory: newest first, original last.
+	exp := []PastName{
+		{Name: "first", Until: msToTime(4000)},
+		{Name: "second", Until: msToTime(3000)},
+		{Name: "first", Until: msToTime(2000)},
+	}
+	if name, hist, _ := s.NameHistory(context.Background(), id); name != "current" || !reflect.DeepEqual(hist, exp) {
+		t.Errorf("NameHistory(ctx, %q) was %q, %v; want \"current\", %v", id, name, hist, exp)
+	}
+}
+
+var testAddObservationInput = [...]struct {
+	obs        []NameObservation
+	name       string
+	seen       int64
+	expObs     []NameObservation
+	expChanged bool
+}{
+	{ // Within the period of the same username
+		obs:        []NameObservation{{"a", msToTime(1000), msToTime(3000)}},
+		name:       "a",
+		seen:       2000,
+		expObs:     []NameObservation{{"a", msToTime(1000), msToTime(3000)}},
+		expChanged: false,
+	},
+	{ // Within the period of another username
+		obs:  []NameObservation{{"a", msToTime(1000), msToTime(3000)}, {"c", msToTime(4000), msToTime(4000)}},
+		name: "b",
+		seen: 2000,
+		expObs: []NameObservation{
+			{"a", msToTime(1000), msToTime(1000)},
+			{"b", msToTime(2000), msToTime(2000)},
+			{"a", msToTime(3000), msToTime(3000)},
+			{"c", msToTime(4000), msToTime(4000)},
+		},
+		expChanged: true,
+	},
+	{ // Contradicting an observation made at the same instant
+		obs:        []NameObservation{{"a", msToTime(1000), msToTime(3000)}},
+		name:       "b",
+		seen:       3000,
+		expObs:     []NameObservation{{"a", msToTime(1000), msToTime(3000)}},
+		expChanged: false,
+	},
+	{ // Before every period
+		obs:        []NameObservation{{"b", msToTime(2000), msToTime(3000)}},
+		name:       "a",
+		seen:       1000,
+		expObs:     []NameObservation{{"a", msToTime(1000), msToTime(1000)}, {"b", msToTime(2000), msToTime(3000)}},
+		expChanged: true,
+	},
+	{ // Extending the following period backwards
+		obs:        []NameObservation{{"a", msToTime(1000), msToTime(1000)}, {"b", msToTime(3000), msToTime(4000)}},
+		name:       "b",
+		seen:       2000,
+		expObs:     []NameObservation{{"a", msToTime(1000), msToTime(1000)}, {"b", msToTime(2000), msToTime(4000)}},
+		expChanged: true,
+	},
+	{ // Extending the preceding period forwards
+		obs:        []NameObservation{{"a", msToTime(1000), msToTime(1000)}, {"b", msToTime(3000), msToTime(4000)}},
+		name:       "a",
+		seen:       2000,
+		expObs:     []NameObservation{{"a", msToTime(1000), msToTime(2000)}, {"b", msToTime(3000), msToTime(4000)}},
+		expChanged: true,
+	},
+	{ // Merging the surrounding periods
+		obs:        []NameObservation{{"a", msToTime(1000), msToTime(1000)}, {"a", msToTime(3000), msToTime(4000)}},
+		name:       "a",
+		seen:       2000,
+		expObs:     []NameObservation{{"a", msToTime(1000), msToTime(4000)}},
+		expChanged: true,
+	},
+}
+
+func TestAddObservation(t *testing.T) {
+	for _, tc := range testAddObservationInput {
+		in := append([]NameObservation(nil), tc.obs...) // Modified in place
+		obs, changed := addObservation(in, tc.name, msToTime(tc.seen))
+		if !reflect.DeepEqual(obs, tc.expObs) || changed != tc.expChanged {
+			t.Errorf("addObservation(%v, %q, %d) was %v, %t; want %v, %t", tc.obs, tc.name, tc.seen, obs, changed, tc.expObs, tc.expChanged)
+		}
+	}
+}
+
+func TestHistoryStoreOutOfOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const id = "087cc153c3434ff7ac497de1569affa1"
+	s, _ := NewHistoryStore(dir)
+	s.ObserveName(id, "first", msToTime(1000))
+	s.ObserveName(id, "first", msToTime(3000))
+	s.ObserveName(id, "current", msToTime(4000))
+	s.ObserveName(id, "second", msToTime(2000)) // Used between the sightings of "first"
+
+	expObs := []NameObservation{
+		{Name: "first", FirstSeen: msToTime(1000), LastSeen: msToTime(1000)},
+		{Name: "second", FirstSeen: msToTime(2000), LastSeen: msToTime(2000)},
+		{Name: "first", FirstSeen: msToTime(3000), LastSeen: msToTime(3000)},
+		{Name: "current", FirstSeen: msToTime(4000), LastSeen: msToTime(4000)},
+	}
+	if obs := s.Observations(id); !reflect.DeepEqual(obs, expObs) {
+		t.Errorf("Observations(%q) was %v; want %v", id, obs, expObs)
+	}
+
+	exp := []PastName{
+		{Name: "first", Until: msToTime(4000)},
+		{Name: "second", Until: msToTime(3000)},
+		{Name: "first", Until: msToTime(2000)},
+	}
+	if name, hist, _ := s.NameHistory(context.Background(), id); name != "current" || !reflect.DeepEqual(hist, exp) {
+		t.Errorf("NameHistory(ctx, %q) was %q, %v; want \"current\", %v", id, name, hist, exp)
+	}
+}
+
+func TestClientObserver(t *testing.T) {
+	t.Parallel()
+
+	obs := &recordingObserver{}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: http.NewFileTransport(http.Dir("testdata"))},
+		Cache:      NewMemoryCache(0),
+		Observer:   obs,
+	}
+	ctx := context.Background()
+
+	before := time.Now()
+	c.Load(ctx, "nergalic")
+	c.Load(ctx, "nergalic") // Served from the cache
+	c.LoadMany(ctx, "breesakana", "nergalic")
+	c.LoadWithProperties(ctx, "087cc153c3434ff7ac497de1569affa1")
+
+	exp := []string{
+		"087cc153c3434ff7ac497de1569affa1 Nergalic",   // Load
+		"d9a5b542ce88442aaab38ec13e6c7773 BreeSakana", // LoadMany
+		"087cc153c3434ff7ac497de1569affa1 Nergalic",   // LoadMany; testdata always responds with both
+		"087cc153c3434ff7ac497de1569affa1 Nergalic",   // LoadWithProperties
+	}
+	if !reflect.DeepEqual(obs.names, exp) {
+		t.Errorf("Client observed %q; want %q", obs.names, exp)
+	}
+	if obs.last.Before(before) {
+		t.Errorf("Client observed names at %s; want after %s", obs.last, before)
+	}
+}
+
+func TestClientHistoryStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, _ := NewHistoryStore(dir)
+	c := &Client{
+		HTTPClient:    &http.Client{Transport: http.NewFileTransport(http.Dir("testdata"))},
+		Observer:      s,
+		HistorySource: s,
+	}
+	ctx := context.Background()
+
+	if _, err := c.LoadWithNameHistory(ctx, "087cc153c3434ff7ac497de1569affa1"); err != nil {
+		t.Fatalf("LoadWithNameHistory(ctx, \"087cc153c3434ff7ac497de1569affa1\") failed: %s", err)
+	}
+	pr := &Profile{ID: "087cc153c3434ff7ac497de1569affa1"}
+	if hist, err := c.LoadNameHistory(ctx, pr, false); hist == nil || len(hist) != 0 || err != nil || pr.Name != "Nergalic" {
+		t.Errorf("LoadNameHistory(ctx, %v, false) was %v, %s with name %q; want [], <nil> with name \"Nergalic\"", pr, hist, p(err), pr.Name)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// fakeHistorySource returns the given name history and records the ID it
+// was requested for.
+type fakeHistorySource struct {
+	name string
+	hist []PastName
+	err  error
+	id   ID
+}
+
+func (s *fakeHistorySource) NameHistory(ctx context.Context, id ID) (string, []PastName, error) {
+	s.id = id
+	if s.err != nil {
+		return "", nil, s.err
+	}
+	return s.name, s.hist, nil
+}
+
+// recordingObserver records the IDs and names it observes.
+type recordingObserver struct {
+	mu    sync.Mutex
+	names []string
+	last  time.Time
+}
+
+func (o *recordingObserver) ObserveName(id ID, name string, seen time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.names = append(o.names, string(id)+" "+name)
+	o.last = seen
+}
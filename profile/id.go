@@ -0,0 +1,117 @@
+package profile
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+)
+
+// ID is the universally unique identifier of a profile. Valid IDs are
+// formatted as 32 lower-case hexadecimal digits without dashes, which is the
+// form used by the Mojang API. Use ParseID to convert IDs given in other forms.
+type ID string
+
+// ParseID parses s as a profile ID. s may be given either as 32 hexadecimal
+// digits or dashed in the 8-4-4-4-12 form of UUIDs, and in any case. If s is
+// not a valid ID, an *InvalidIDError is returned.
+func ParseID(s string) (ID, error) {
+	var buf [32]byte
+	switch len(s) {
+	case 32:
+		copy(buf[:], s)
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return "", &InvalidIDError{ID: s}
+		}
+		copy(buf[0:8], s[0:8])
+		copy(buf[8:12], s[9:13])
+		copy(buf[12:16], s[14:18])
+		copy(buf[16:20], s[19:23])
+		copy(buf[20:32], s[24:36])
+	default:
+		return "", &InvalidIDError{ID: s}
+	}
+
+	for i, c := range buf {
+		switch {
+		case '0' <= c && c <= '9', 'a' <= c && c <= 'f':
+		case 'A' <= c && c <= 'F':
+			buf[i] = c - 'A' + 'a'
+		default:
+			return "", &InvalidIDError{ID: s}
+		}
+	}
+	return ID(buf[:]), nil
+}
+
+// String returns id formatted as 32 hexadecimal digits without dashes.
+func (id ID) String() string {
+	return string(id)
+}
+
+// Dashed returns id formatted in the dashed 8-4-4-4-12 form of UUIDs, e.g.
+// "087cc153-c343-4ff7-ac49-7de1569affa1". If id is not valid, Dashed returns
+// string(id).
+func (id ID) Dashed() string {
+	if len(id) != 32 {
+		return string(id)
+	}
+	s := string(id)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
+
+// OfflineID returns the ID which a server running in offline mode assigns to
+// the player with the given username. Offline servers don't authenticate
+// players with Mojang, and instead derive their IDs from their usernames as
+// the name-based version 3 UUID of "OfflinePlayer:" + username.
+//
+// Note that usernames are case sensitive in this derivation, so "Notch" and
+// "notch" have different offline IDs.
+func OfflineID(username string) ID {
+	sum := md5.Sum([]byte("OfflinePlayer:" + username))
+	sum[6] = sum[6]&0x0f | 0x30 // Version 3
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return ID(hex.EncodeToString(sum[:]))
+}
+
+// Version returns the UUID version of id, or 0 if id is not valid.
+// IDs assigned by Mojang are version 4 (random) UUIDs, while IDs derived by
+// servers running in offline mode are version 3 (name-based) UUIDs.
+func (id ID) Version() int {
+	if parsed, err := ParseID(string(id)); err != nil || parsed != id {
+		return 0
+	}
+	v := id[12]
+	if v <= '9' {
+		return int(v - '0')
+	}
+	return int(v-'a') + 10
+}
+
+// IsOffline reports whether id is a version 3 UUID, which is the kind of ID
+// servers running in offline mode derive from usernames. See OfflineID.
+func (id ID) IsOffline() bool {
+	return id.Version() == 3
+}
+
+// IsOnline reports whether id is a version 4 UUID, which is the kind of ID
+// Mojang assigns to profiles.
+func (id ID) IsOnline() bool {
+	return id.Version() == 4
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// id is encoded as 32 hexadecimal digits without dashes.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The ID is expected in a form accepted by ParseID.
+func (id *ID) UnmarshalText(text []byte) error {
+	parsed, err := ParseID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
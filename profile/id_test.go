@@ -0,0 +1,160 @@
+package profile
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var testParseIDInput = [...]struct {
+	s      string
+	expID  ID
+	expErr error
+}{
+	{
+		s:     "087cc153c3434ff7ac497de1569affa1",
+		expID: "087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		s:     "087CC153C3434FF7AC497DE1569AFFA1",
+		expID: "087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		s:     "087cc153-c343-4ff7-ac49-7de1569affa1",
+		expID: "087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		s:     "087CC153-c343-4FF7-ac49-7DE1569AFFA1",
+		expID: "087cc153c3434ff7ac497de1569affa1",
+	},
+	{
+		s:      "",
+		expErr: &InvalidIDError{ID: ""},
+	},
+	{
+		s:      "087cc153c3434ff7ac497de1569affa",
+		expErr: &InvalidIDError{ID: "087cc153c3434ff7ac497de1569affa"},
+	},
+	{
+		s:      "087cc153c3434ff7ac497de1569affag",
+		expErr: &InvalidIDError{ID: "087cc153c3434ff7ac497de1569affag"},
+	},
+	{
+		s:      "087cc153-c3434-ff7-ac49-7de1569affa1",
+		expErr: &InvalidIDError{ID: "087cc153-c3434-ff7-ac49-7de1569affa1"},
+	},
+	{
+		s:      "087cc153+c343+4ff7+ac49+7de1569affa1",
+		expErr: &InvalidIDError{ID: "087cc153+c343+4ff7+ac49+7de1569affa1"},
+	},
+	{
+		s:      "!BAD_ID!f3fd461daff5086b22154bce",
+		expErr: &InvalidIDError{ID: "!BAD_ID!f3fd461daff5086b22154bce"},
+	},
+}
+
+func TestParseID(t *testing.T) {
+	for _, tc := range testParseIDInput {
+		id, err := ParseID(tc.s)
+		if id != tc.expID || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("ParseID(%q) was %q, %s; want %q, %s", tc.s, id, p(err), tc.expID, p(tc.expErr))
+		}
+	}
+}
+
+func TestID_Format(t *testing.T) {
+	const id ID = "087cc153c3434ff7ac497de1569affa1"
+	if s := id.String(); s != "087cc153c3434ff7ac497de1569affa1" {
+		t.Errorf("ID(%q).String() was %q; want %q", string(id), s, string(id))
+	}
+	if s := id.Dashed(); s != "087cc153-c343-4ff7-ac49-7de1569affa1" {
+		t.Errorf("ID(%q).Dashed() was %q; want %q", string(id), s, "087cc153-c343-4ff7-ac49-7de1569affa1")
+	}
+	if s := ID("bad").Dashed(); s != "bad" {
+		t.Errorf("ID(%q).Dashed() was %q; want %q", "bad", s, "bad")
+	}
+}
+
+func TestID_JSON(t *testing.T) {
+	var v struct{ ID ID }
+	if err := json.Unmarshal([]byte(`{"ID":"087CC153-C343-4FF7-AC49-7DE1569AFFA1"}`), &v); err != nil {
+		t.Fatalf("Unmarshalling dashed ID failed: %s", err)
+	}
+	if v.ID != "087cc153c3434ff7ac497de1569affa1" {
+		t.Errorf("Unmarshalling dashed ID produced %q; want %q", v.ID, "087cc153c3434ff7ac497de1569affa1")
+	}
+
+	bs, err := json.Marshal(v)
+	if exp := `{"ID":"087cc153c3434ff7ac497de1569affa1"}`; string(bs) != exp || err != nil {
+		t.Errorf("Marshalling ID produced %s, %s; want %s, <nil>", bs, p(err), exp)
+	}
+
+	err = json.Unmarshal([]byte(`{"ID":"bad"}`), &v)
+	if _, ok := err.(*InvalidIDError); !ok {
+		t.Errorf("Unmarshalling invalid ID returned %s; want *InvalidIDError", p(err))
+	}
+}
+
+var testOfflineIDInput = [...]struct {
+	username string
+	expID    ID
+}{
+	{
+		username: "Notch",
+		expID:    "b50ad385829d3141a2167e7d7539ba7f",
+	},
+	{
+		username: "notch", // Case sensitive
+		expID:    "42653081a90e3475b3d63550cdb43f8e",
+	},
+}
+
+func TestOfflineID(t *testing.T) {
+	for _, tc := range testOfflineIDInput {
+		if id := OfflineID(tc.username); id != tc.expID {
+			t.Errorf("OfflineID(%q) was %q; want %q", tc.username, id, tc.expID)
+		}
+	}
+}
+
+var testIDVersionInput = [...]struct {
+	id         ID
+	expVersion int
+	expOffline bool
+	expOnline  bool
+}{
+	{
+		id:         "087cc153c3434ff7ac497de1569affa1", // Nergalic
+		expVersion: 4,
+		expOnline:  true,
+	},
+	{
+		id:         "b50ad385829d3141a2167e7d7539ba7f", // Offline Notch
+		expVersion: 3,
+		expOffline: true,
+	},
+	{
+		id:         "00000000000000000000000000000000",
+		expVersion: 0,
+	},
+	{
+		id:         "B50AD385829D3141A2167E7D7539BA7F", // Not canonical
+		expVersion: 0,
+	},
+	{
+		id:         "bad",
+		expVersion: 0,
+	},
+}
+
+func TestID_Version(t *testing.T) {
+	for _, tc := range testIDVersionInput {
+		v, off, on := tc.id.Version(), tc.id.IsOffline(), tc.id.IsOnline()
+		if v != tc.expVersion || off != tc.expOffline || on != tc.expOnline {
+			t.Errorf(
+				"ID(%q): Version(), IsOffline(), IsOnline() was %d, %t, %t; want %d, %t, %t",
+				string(tc.id), v, off, on, tc.expVersion, tc.expOffline, tc.expOnline,
+			)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package profile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+// pastNameJSON is an entry of a name history as served by Mojang.
+type pastNameJSON struct {
+	Name        string `json:"name"`
+	ChangedToAt int64  `json:"changedToAt,omitempty"` // Milliseconds since the Unix epoch
+}
+
+// propertyJSON is a profile property as served by Mojang.
+type propertyJSON struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// profileJSON is a profile as served by Mojang's profile lookups and session
+// server, extended with the name history of the profile as served by
+// Mojang's former name history endpoint. Profile IDs are kept as strings
+// rather than decoded as ID, so that fillProfile and populateTextures can
+// report malformed IDs along with their path.
+type profileJSON struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Legacy      bool           `json:"legacy,omitempty"`
+	Demo        bool           `json:"demo,omitempty"`
+	NameHistory []pastNameJSON `json:"nameHistory,omitempty"`
+	Properties  []propertyJSON `json:"properties,omitempty"`
+}
+
+// texturesJSON is the decoded value of a textures property.
+type texturesJSON struct {
+	Timestamp         int64                  `json:"timestamp,omitempty"`
+	ProfileID         string                 `json:"profileId"`
+	ProfileName       string                 `json:"profileName,omitempty"`
+	SignatureRequired bool                   `json:"signatureRequired,omitempty"`
+	Textures          map[string]textureJSON `json:"textures"`
+}
+
+type textureJSON struct {
+	URL      string            `json:"url"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. p is encoded like the
+// profiles served by Mojang's session server, i.e. with the keys "id",
+// "name" and, if loaded, "properties". If p.NameHistory is loaded, it is
+// included as "nameHistory" in the shape formerly served by Mojang's name
+// history endpoint, ending with p.Name. Like for PastName, changedToAt is
+// omitted where the time of a name change is unknown.
+func (p Profile) MarshalJSON() ([]byte, error) {
+	js := profileJSON{ID: string(p.ID), Name: p.Name}
+	if p.NameHistory != nil {
+		js.NameHistory = make([]pastNameJSON, 0, len(p.NameHistory)+1)
+		var changedToAt int64
+		for i := len(p.NameHistory) - 1; i >= 0; i-- { // Oldest first
+			past := p.NameHistory[i]
+			js.NameHistory = append(js.NameHistory, pastNameJSON{Name: past.Name, ChangedToAt: changedToAt})
+			changedToAt = 0
+			if !past.Until.IsZero() {
+				changedToAt = timeToMs(past.Until)
+			}
+		}
+		js.NameHistory = append(js.NameHistory, pastNameJSON{Name: p.Name, ChangedToAt: changedToAt})
+	}
+	if p.Properties != nil {
+		props, err := p.Properties.toJSON()
+		if err != nil {
+			return nil, err
+		}
+		js.Properties = props
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The profile is
+// decoded from the shape produced by MarshalJSON, which also is the shape of
+// profiles served by the Mojang servers. Demo profiles are rejected with
+// ErrNoSuchProfile.
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	var js profileJSON
+	if err := internal.Decode(data, &js); err != nil {
+		return err
+	}
+
+	pr := Profile{}
+	ok, err := fillProfile(&pr, &js)
+	if err != nil {
+		return err
+	} else if !ok {
+		return ErrNoSuchProfile
+	}
+	if js.NameHistory != nil {
+		if _, pr.NameHistory, err = buildHistory(js.NameHistory); err != nil {
+			return internal.AtPath(err, "nameHistory")
+		}
+		if pr.NameHistory == nil {
+			pr.NameHistory = emptyHist
+		}
+	}
+	if js.Properties != nil {
+		if pr.Properties, err = decodeProperties(js.Properties); err != nil {
+			return internal.AtPath(err, "properties")
+		}
+	}
+
+	*p = pr
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. p is encoded like an
+// entry of a name history formerly served by Mojang, with the keys "name"
+// and "changedToAt". Note that changedToAt holds p.Until in milliseconds
+// since the Unix epoch, i.e. when the profile changed to its next username.
+// It is omitted if p.Until is zero.
+func (p PastName) MarshalJSON() ([]byte, error) {
+	js := pastNameJSON{Name: p.Name}
+	if !p.Until.IsZero() {
+		js.ChangedToAt = timeToMs(p.Until)
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The past username
+// is decoded from the shape produced by MarshalJSON.
+func (p *PastName) UnmarshalJSON(data []byte) error {
+	var js pastNameJSON
+	if err := internal.Decode(data, &js); err != nil {
+		return err
+	}
+	*p = PastName{Name: js.Name}
+	if js.ChangedToAt != 0 {
+		p.Until = msToTime(js.ChangedToAt)
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. p is encoded like the
+// properties of a profile served by Mojang's session server: an array of
+// objects with the keys "name", "value" and, if signed, "signature". If p.Raw
+// is set, it is encoded as is. Otherwise the textures property is encoded
+// from p.Textures, from which SkinURL, CapeURL and Model are derived when
+// decoded. Properties with a skin, cape or model but no Textures, which
+// only can be constructed by hand, cannot be encoded.
+func (p Properties) MarshalJSON() ([]byte, error) {
+	props, err := p.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	if props == nil {
+		props = []propertyJSON{}
+	}
+	return json.Marshal(props)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The properties
+// are decoded from the shape produced by MarshalJSON, which also is the
+// shape of properties served by the Mojang servers. If any property is
+// signed, p.Raw is set.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	var js []propertyJSON
+	if err := internal.Decode(data, &js); err != nil {
+		return err
+	}
+	ps, err := decodeProperties(js)
+	if err != nil {
+		return err
+	}
+	*p = *ps
+	return nil
+}
+
+// decodeProperties builds properties from a JSON array of properties like
+// buildProperties, keeping them as Raw if any is signed.
+func decodeProperties(arr []propertyJSON) (*Properties, error) {
+	signed := false
+	for _, prop := range arr {
+		if prop.Signature != "" {
+			signed = true
+		}
+	}
+	return buildProperties(arr, signed)
+}
+
+// toJSON returns the wire representation of p.
+func (p *Properties) toJSON() ([]propertyJSON, error) {
+	if p.Raw != nil {
+		props := make([]propertyJSON, len(p.Raw))
+		for i, prop := range p.Raw {
+			props[i] = propertyJSON{Name: prop.Name, Value: prop.Value, Signature: prop.Signature}
+		}
+		return props, nil
+	}
+
+	t := p.Textures
+	if t == nil {
+		if p.SkinURL != "" || p.CapeURL != "" || p.Model != Steve {
+			return nil, errors.New("minecraft/profile: cannot marshal properties without textures")
+		}
+		return nil, nil
+	}
+
+	js := texturesJSON{
+		ProfileID:         string(t.ProfileID),
+		ProfileName:       t.ProfileName,
+		SignatureRequired: t.SignatureRequired,
+		Textures:          make(map[string]textureJSON, len(t.Textures)),
+	}
+	if !t.Timestamp.IsZero() {
+		js.Timestamp = timeToMs(t.Timestamp)
+	}
+	for typ, tex := range t.Textures {
+		js.Textures[typ] = textureJSON{URL: tex.URL, Metadata: tex.Metadata}
+	}
+	bs, err := json.Marshal(js)
+	if err != nil {
+		return nil, err
+	}
+	return []propertyJSON{{Name: "textures", Value: base64.StdEncoding.EncodeToString(bs)}}, nil
+}
@@ -0,0 +1,236 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/internal"
+)
+
+func TestProfileJSON(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{HTTPClient: &http.Client{Transport: http.NewFileTransport(http.Dir("testdata"))}}
+	pr, err := c.LoadWithProperties(context.Background(), "087cc153c3434ff7ac497de1569affa1")
+	if err != nil {
+		t.Fatalf("LoadWithProperties(ctx, \"087cc153c3434ff7ac497de1569affa1\") failed: %s", err)
+	}
+	pr.NameHistory = []PastName{
+		{Name: "Second", Until: msToTime(1423047705000)},
+		{Name: "GeneralSezuan", Until: msToTime(1300000000000)},
+	}
+
+	bs, err := json.Marshal(pr)
+	if err != nil {
+		t.Fatalf("json.Marshal(%#v) failed: %s", pr, err)
+	}
+	exp := `{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic",` +
+		`"nameHistory":[{"name":"GeneralSezuan"},{"name":"Second","changedToAt":1300000000000},{"name":"Nergalic","changedToAt":1423047705000}],` +
+		`"properties":[{"name":"textures","value":"` + nergalicTextures + `"}]}`
+	if !equalJSON(t, bs, []byte(exp)) {
+		t.Errorf("json.Marshal(%#v) was\n%s\nwant\n%s", pr, bs, exp)
+	}
+
+	var dec Profile
+	if err := json.Unmarshal(bs, &dec); err != nil || !reflect.DeepEqual(&dec, pr) {
+		t.Errorf("json.Unmarshal(%s)\n was: %#v, %s\nwant: %#v, <nil>", bs, &dec, p(err), pr)
+	}
+}
+
+var testProfileJSONInput = [...]struct {
+	profile Profile
+	expJSON string
+}{
+	{
+		Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"},
+		`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic"}`,
+	},
+	{
+		Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic", NameHistory: emptyHist},
+		`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","nameHistory":[{"name":"Nergalic"}]}`,
+	},
+	{
+		Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic", Properties: &Properties{}},
+		`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic"}`,
+	},
+}
+
+func TestProfileJSONShapes(t *testing.T) {
+	for _, tc := range testProfileJSONInput {
+		bs, err := json.Marshal(tc.profile)
+		if err != nil || string(bs) != tc.expJSON {
+			t.Errorf("json.Marshal(%#v) was %s, %s; want %s, <nil>", tc.profile, bs, p(err), tc.expJSON)
+		}
+	}
+
+	var dec Profile
+	js := `{"id":"087CC153-C343-4FF7-AC49-7DE1569AFFA1","name":"Nergalic","legacy":true}`
+	exp := Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic", NameHistory: emptyHist}
+	if err := json.Unmarshal([]byte(js), &dec); err != nil || !reflect.DeepEqual(dec, exp) {
+		t.Errorf("json.Unmarshal(%s) was %#v, %s; want %#v, <nil>", js, dec, p(err), exp)
+	}
+}
+
+// Test that name changes at unknown times survive a round trip.
+func TestProfileJSONUnknownUntil(t *testing.T) {
+	pr := Profile{
+		ID:   "087cc153c3434ff7ac497de1569affa1",
+		Name: "Nergalic",
+		NameHistory: []PastName{
+			{Name: "Second"},
+			{Name: "GeneralSezuan", Until: msToTime(1300000000000)},
+		},
+	}
+	exp := `{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic",` +
+		`"nameHistory":[{"name":"GeneralSezuan"},{"name":"Second","changedToAt":1300000000000},{"name":"Nergalic"}]}`
+
+	bs, err := json.Marshal(pr)
+	if err != nil || string(bs) != exp {
+		t.Errorf("json.Marshal(%#v) was %s, %s; want %s, <nil>", pr, bs, p(err), exp)
+	}
+	var dec Profile
+	if err := json.Unmarshal(bs, &dec); err != nil || !reflect.DeepEqual(dec, pr) {
+		t.Errorf("json.Unmarshal(%s)\n was: %#v, %s\nwant: %#v, <nil>", bs, dec, p(err), pr)
+	}
+}
+
+var testProfileJSONErrorsInput = [...]struct {
+	json   string
+	expErr error
+}{
+	{`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","demo":true}`, ErrNoSuchProfile},
+	{`{"id":"087cc153c3434ff7ac497de1569affa1"}`, &internal.FormatError{Path: "name", Expected: "string", Actual: "missing"}},
+	{`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","nameHistory":{}}`, &internal.FormatError{Path: "nameHistory", Expected: "array", Actual: "object"}},
+	{`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","nameHistory":[{"name":"A"},{"name":"B","changedToAt":"yesterday"}]}`, &internal.FormatError{Path: "nameHistory.1.changedToAt", Expected: "integer", Actual: "string"}},
+	{`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","nameHistory":[{"name":"A"},{"changedToAt":1423047705000}]}`, &internal.FormatError{Path: "nameHistory.1.name", Expected: "string", Actual: "missing"}},
+	{`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","properties":[{"value":"dmFsdWU="}]}`, &internal.FormatError{Path: "properties.0.name", Expected: "string", Actual: "missing"}},
+	{`["087cc153c3434ff7ac497de1569affa1"]`, &internal.FormatError{Path: "", Expected: "object", Actual: "array"}},
+}
+
+func TestProfileJSONErrors(t *testing.T) {
+	for _, tc := range testProfileJSONErrorsInput {
+		var dec Profile
+		if err := json.Unmarshal([]byte(tc.json), &dec); !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("json.Unmarshal(%s) returned %s; want %s", tc.json, p(err), tc.expErr)
+		}
+	}
+}
+
+func TestPastNameJSON(t *testing.T) {
+	for _, tc := range []struct {
+		past    PastName
+		expJSON string
+	}{
+		{PastName{Name: "GeneralSezuan", Until: msToTime(1423047705000)}, `{"name":"GeneralSezuan","changedToAt":1423047705000}`},
+		{PastName{Name: "GeneralSezuan"}, `{"name":"GeneralSezuan"}`},
+	} {
+		bs, err := json.Marshal(tc.past)
+		if err != nil || string(bs) != tc.expJSON {
+			t.Errorf("json.Marshal(%#v) was %s, %s; want %s, <nil>", tc.past, bs, p(err), tc.expJSON)
+		}
+		var dec PastName
+		if err := json.Unmarshal(bs, &dec); err != nil || !reflect.DeepEqual(dec, tc.past) {
+			t.Errorf("json.Unmarshal(%s) was %#v, %s; want %#v, <nil>", bs, dec, p(err), tc.past)
+		}
+	}
+
+	js := `{"name":"GeneralSezuan","changedToAt":"yesterday"}`
+	exp := &internal.FormatError{Path: "changedToAt", Expected: "integer", Actual: "string"}
+	var dec PastName
+	if err := json.Unmarshal([]byte(js), &dec); !reflect.DeepEqual(err, exp) {
+		t.Errorf("json.Unmarshal(%s) returned %s; want %s", js, p(err), exp)
+	}
+}
+
+func TestPropertiesJSON(t *testing.T) {
+	signed := Properties{Raw: []Property{
+		{Name: "textures", Value: nergalicTextures, Signature: "c2lnbmF0dXJl"},
+		{Name: "unknown", Value: "dmFsdWU="},
+	}}
+	bs, err := json.Marshal(&signed)
+	exp := `[{"name":"textures","value":"` + nergalicTextures + `","signature":"c2lnbmF0dXJl"},{"name":"unknown","value":"dmFsdWU="}]`
+	if err != nil || string(bs) != exp {
+		t.Fatalf("json.Marshal(%#v) was %s, %s; want %s, <nil>", signed, bs, p(err), exp)
+	}
+
+	var dec Properties
+	if err := json.Unmarshal(bs, &dec); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %s", bs, err)
+	}
+	if !reflect.DeepEqual(dec.Raw, signed.Raw) || dec.Textures == nil || dec.Textures.Timestamp != msToTime(1495799175553) {
+		t.Errorf("json.Unmarshal(%s) was %#v; want Raw %#v and textures decoded", bs, dec, signed.Raw)
+	}
+
+	// The textures property is encoded from Textures when unsigned, keeping
+	// metadata and the Alex model.
+	alex := &Properties{
+		SkinURL: "http://textures.minecraft.net/texture/abc",
+		Model:   Alex,
+		Textures: &Textures{
+			Timestamp: msToTime(1495799175553),
+			ProfileID: "087cc153c3434ff7ac497de1569affa1",
+			Textures: map[string]Texture{
+				SkinTexture: {URL: "http://textures.minecraft.net/texture/abc", Hash: "abc", Metadata: map[string]string{"model": "slim"}},
+			},
+		},
+	}
+	if bs, err = json.Marshal(alex); err != nil {
+		t.Fatalf("json.Marshal(%#v) failed: %s", alex, err)
+	}
+	dec = Properties{}
+	if err := json.Unmarshal(bs, &dec); err != nil || !reflect.DeepEqual(&dec, alex) {
+		t.Errorf("json.Unmarshal(%s)\n was: %#v, %s\nwant: %#v, <nil>", bs, &dec, p(err), alex)
+	}
+
+	if bs, err := json.Marshal(Properties{}); err != nil || string(bs) != "[]" {
+		t.Errorf("json.Marshal(Properties{}) was %s, %s; want [], <nil>", bs, p(err))
+	}
+	if _, err := json.Marshal(Properties{SkinURL: "http://textures.minecraft.net/texture/abc"}); err == nil {
+		t.Error("json.Marshal(<properties without textures>) succeeded; want error")
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// equalJSON reports whether a and b encode equal JSON values. The base64
+// encoded textures property is compared after decoding, as key order and
+// whitespace may differ.
+func equalJSON(t *testing.T, a, b []byte) bool {
+	var ja, jb interface{}
+	if err := json.Unmarshal(a, &ja); err != nil {
+		t.Fatalf("invalid JSON %s: %s", a, err)
+	}
+	if err := json.Unmarshal(b, &jb); err != nil {
+		t.Fatalf("invalid JSON %s: %s", b, err)
+	}
+	return reflect.DeepEqual(decodeTextures(ja), decodeTextures(jb))
+}
+
+// decodeTextures replaces the values of textures properties within js by
+// their decoded JSON values.
+func decodeTextures(js interface{}) interface{} {
+	switch v := js.(type) {
+	case map[string]interface{}:
+		if v["name"] == "textures" {
+			if s, ok := v["value"].(string); ok {
+				var ps Properties
+				if populateTextures(s, &ps) == nil {
+					v["value"] = ps.Textures
+				}
+			}
+		}
+		for k, e := range v {
+			v[k] = decodeTextures(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = decodeTextures(e)
+		}
+	}
+	return js
+}
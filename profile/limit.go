@@ -0,0 +1,144 @@
+package profile
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Rate limits of the Mojang API at the time of writing. See the documentation
+// of ErrTooManyRequests.
+const (
+	// SharedRateLimit is the number of load requests which may be performed
+	// within SharedRateWindow.
+	SharedRateLimit = 600
+	// SharedRateWindow is the time window of SharedRateLimit.
+	SharedRateWindow = 10 * time.Minute
+	// PropertiesCooldown is the time which must pass between two requests
+	// for the properties of the same profile.
+	PropertiesCooldown = time.Minute
+)
+
+// A Limiter keeps a Client within the rate limits of the Mojang API, so
+// requests which would be rejected by the Mojang servers are not performed.
+// Load requests share a token bucket holding SharedRateLimit tokens, which
+// are regained evenly over SharedRateWindow. Properties requests are limited
+// to one per profile per PropertiesCooldown.
+//
+// The same Limiter may be shared by several Clients which communicate with
+// the Mojang servers from the same IP address. A Limiter is safe for
+// concurrent use by multiple goroutines.
+type Limiter struct {
+	block    bool
+	burst    float64       // Capacity of shared token bucket
+	interval time.Duration // Time to regain one shared token
+	cooldown time.Duration // Time between properties requests per profile
+
+	mu        sync.Mutex
+	tokens    float64
+	last      time.Time
+	cooldowns map[string]time.Time // Profile ID -> end of cooldown
+	purgeAt   int                  // Size of cooldowns triggering a purge
+
+	now func() time.Time
+}
+
+// NewLimiter returns a Limiter modelling Mojang's documented rate limits.
+// If block is true, requests which would exceed a rate limit wait until they
+// may be performed or their context is done, in which case the context's
+// error is returned. Requests whose context deadline falls before they may be
+// performed don't wait, but fail immediately with a *RateLimitError. If block
+// is false, all such requests fail immediately with a *RateLimitError.
+func NewLimiter(block bool) *Limiter {
+	return newLimiter(block, SharedRateLimit, SharedRateWindow/SharedRateLimit, PropertiesCooldown)
+}
+
+func newLimiter(block bool, burst int, interval, cooldown time.Duration) *Limiter {
+	return &Limiter{
+		block:     block,
+		burst:     float64(burst),
+		interval:  interval,
+		cooldown:  cooldown,
+		tokens:    float64(burst),
+		cooldowns: make(map[string]time.Time),
+		purgeAt:   64,
+		now:       time.Now,
+	}
+}
+
+// wait returns once a request may be performed. If id != "", the request is
+// a properties request for the profile identified by id. Otherwise it is a
+// load request drawing from the shared budget.
+func (l *Limiter) wait(ctx context.Context, id string) error {
+	for {
+		d := l.reserve(id)
+		if d <= 0 {
+			return nil
+		}
+		if !l.block {
+			return &RateLimitError{Wait: d}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+			return &RateLimitError{Wait: d} // Waiting would be in vain
+		}
+
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve reserves room for a request, returning 0, or returns the time
+// until room can be reserved.
+func (l *Limiter) reserve(id string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if id != "" {
+		if until, ok := l.cooldowns[id]; ok && now.Before(until) {
+			return until.Sub(now)
+		}
+		l.cooldowns[id] = now.Add(l.cooldown)
+		l.purge(now)
+		return 0
+	}
+
+	if !l.last.IsZero() {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		d := time.Duration((1 - l.tokens) * float64(l.interval))
+		if d <= 0 {
+			d = 1 // Round up any rounding error
+		}
+		return d
+	}
+	l.tokens--
+	return 0
+}
+
+// purge removes expired cooldowns once enough have accumulated.
+func (l *Limiter) purge(now time.Time) {
+	if len(l.cooldowns) < l.purgeAt {
+		return
+	}
+	for id, until := range l.cooldowns {
+		if !now.Before(until) {
+			delete(l.cooldowns, id)
+		}
+	}
+	l.purgeAt = 2 * len(l.cooldowns)
+	if l.purgeAt < 64 {
+		l.purgeAt = 64
+	}
+}
@@ -0,0 +1,189 @@
+package profile
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterShared(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	l := NewLimiter(false)
+	l.now = clock.Now
+	ctx := context.Background()
+
+	for i := 0; i < SharedRateLimit; i++ {
+		if err := l.wait(ctx, ""); err != nil {
+			t.Fatalf("Limiter rejected request %d within the shared rate limit: %s", i+1, err)
+		}
+	}
+
+	interval := SharedRateWindow / SharedRateLimit
+	err := l.wait(ctx, "")
+	if exp := (&RateLimitError{Wait: interval}); !reflect.DeepEqual(err, exp) {
+		t.Fatalf("Limiter exceeding the shared rate limit returned %s; want %s", p(err), exp)
+	}
+
+	clock.Add(interval)
+	if err := l.wait(ctx, ""); err != nil {
+		t.Errorf("Limiter rejected request after regaining a token: %s", err)
+	}
+}
+
+func TestLimiterProperties(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	l := NewLimiter(false)
+	l.now = clock.Now
+	ctx := context.Background()
+
+	const id1, id2 = "087cc153c3434ff7ac497de1569affa1", "cabefc91b5df4c87886a6c604da2e46f"
+
+	if err := l.wait(ctx, id1); err != nil {
+		t.Fatalf("Limiter rejected first properties request for %s: %s", id1, err)
+	}
+	if err := l.wait(ctx, id2); err != nil {
+		t.Fatalf("Limiter rejected first properties request for %s: %s", id2, err)
+	}
+
+	clock.Add(PropertiesCooldown / 4)
+	err := l.wait(ctx, id1)
+	if exp := (&RateLimitError{Wait: PropertiesCooldown * 3 / 4}); !reflect.DeepEqual(err, exp) {
+		t.Fatalf("Limiter repeating properties request for %s returned %s; want %s", id1, p(err), exp)
+	}
+
+	clock.Add(PropertiesCooldown * 3 / 4)
+	if err := l.wait(ctx, id1); err != nil {
+		t.Errorf("Limiter rejected properties request for %s after cooldown: %s", id1, err)
+	}
+
+	// Properties requests don't draw from the shared budget
+	if l.tokens != SharedRateLimit {
+		t.Errorf("Limiter properties requests consumed shared tokens")
+	}
+}
+
+func TestLimiterBlock(t *testing.T) {
+	t.Parallel()
+
+	l := newLimiter(true, 1, 20*time.Millisecond, time.Hour)
+	ctx := context.Background()
+
+	start := time.Now()
+	l.wait(ctx, "")
+	if err := l.wait(ctx, ""); err != nil {
+		t.Fatalf("Blocking Limiter returned %s; want <nil>", err)
+	}
+	if d := time.Since(start); d < 15*time.Millisecond {
+		t.Errorf("Blocking Limiter waited %s for a token; want ~20ms", d)
+	}
+
+	l.wait(ctx, "id")
+	ctx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := l.wait(ctx, "id"); err != context.Canceled {
+		t.Errorf("Blocking Limiter returned %s when context was canceled; want %s", p(err), context.Canceled)
+	}
+}
+
+// Test that a blocking Limiter doesn't wait when the context deadline is
+// before the request may be performed.
+func TestLimiterBlockDeadline(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	l := newLimiter(true, 1, time.Hour, time.Hour)
+	l.now = clock.Now
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, id := range []string{"", "087cc153c3434ff7ac497de1569affa1"} {
+		l.wait(ctx, id)
+		err := l.wait(ctx, id)
+		if exp := (&RateLimitError{Wait: time.Hour}); !reflect.DeepEqual(err, exp) {
+			t.Errorf("Blocking Limiter returned %s for %q when context deadline was before the next slot; want %s", p(err), id, exp)
+		}
+	}
+}
+
+func TestClientLimiter(t *testing.T) {
+	t.Parallel()
+
+	rt := &countingTransport{transport: http.NewFileTransport(http.Dir("testdata"))}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: rt},
+		Limiter:    NewLimiter(false),
+	}
+	ctx := context.Background()
+	const id = "087cc153c3434ff7ac497de1569affa1"
+
+	if _, err := c.LoadWithProperties(ctx, id); err != nil {
+		t.Fatalf("LoadWithProperties(ctx, %q) failed: %s", id, err)
+	}
+	_, err := c.LoadWithProperties(ctx, id)
+	if _, ok := err.(*RateLimitError); !ok {
+		t.Errorf("Repeated LoadWithProperties(ctx, %q) returned %s; want *RateLimitError", id, p(err))
+	}
+	if n := rt.Count(); n != 1 {
+		t.Errorf("Rate limited client performed %d requests; want 1", n)
+	}
+}
+
+// Test that retries of a properties request aren't stopped by the cooldown
+// started by the request itself.
+func TestClientLimiterRetry(t *testing.T) {
+	t.Parallel()
+
+	rt := &countingTransport{transport: &flakyTransport{failures: 1, transport: http.NewFileTransport(http.Dir("testdata"))}}
+	c := &Client{
+		HTTPClient: &http.Client{Transport: rt},
+		Limiter:    NewLimiter(false),
+		Retry:      &RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond},
+	}
+	const id = "087cc153c3434ff7ac497de1569affa1"
+
+	if _, err := c.LoadWithProperties(context.Background(), id); err != nil {
+		t.Fatalf("LoadWithProperties(ctx, %q) after a 503 response failed: %s", id, err)
+	}
+	if n := rt.Count(); n != 2 {
+		t.Errorf("Rate limited client performed %d requests; want 2", n)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// flakyTransport responds 503 Service Unavailable to the first failures
+// requests, and passes later requests on to transport.
+type flakyTransport struct {
+	mu        sync.Mutex
+	failures  int
+	transport http.RoundTripper
+}
+
+func (ft *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ft.mu.Lock()
+	fail := ft.failures > 0
+	ft.failures--
+	ft.mu.Unlock()
+
+	if !fail {
+		return ft.transport.RoundTrip(req)
+	}
+	return &http.Response{
+		StatusCode: 503,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
@@ -2,9 +2,10 @@ package profile
 
 import (
 	"context"
-	"fmt"
-	"net/http"
+	"encoding/json"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/PhilipBorgesen/minecraft/internal"
@@ -15,87 +16,182 @@ import (
 // ErrMaxSizeExceeded error.
 const LoadManyMaxSize int = 100
 
+// Load is a wrapper around DefaultClient.Load.
+func Load(ctx context.Context, username string) (p *Profile, err error) {
+	return DefaultClient.Load(ctx, username)
+}
+
+// LoadAtTime is a wrapper around DefaultClient.LoadAtTime.
+func LoadAtTime(ctx context.Context, username string, t time.Time) (p *Profile, err error) {
+	return DefaultClient.LoadAtTime(ctx, username, t)
+}
+
+// LoadByID is a wrapper around DefaultClient.LoadByID.
+func LoadByID(ctx context.Context, id string) (p *Profile, err error) {
+	return DefaultClient.LoadByID(ctx, id)
+}
+
+// LoadWithNameHistory is a wrapper around DefaultClient.LoadWithNameHistory.
+func LoadWithNameHistory(ctx context.Context, id string) (p *Profile, err error) {
+	return DefaultClient.LoadWithNameHistory(ctx, id)
+}
+
+// LoadWithProperties is a wrapper around DefaultClient.LoadWithProperties.
+//
+// NB! For each profile, profile properties may only be requested once per
+// minute.
+func LoadWithProperties(ctx context.Context, id string) (p *Profile, err error) {
+	return DefaultClient.LoadWithProperties(ctx, id)
+}
+
+// LoadMany is a wrapper around DefaultClient.LoadMany.
+//
+// NB! Only a maximum of LoadManyMaxSize profiles may be fetched at once.
+// If more are attempted loaded in the same operation, an ErrMaxSizeExceeded
+// error is returned.
+func LoadMany(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
+	return DefaultClient.LoadMany(ctx, usernames...)
+}
+
 // Load fetches the profile currently associated with username. ctx must be
 // non-nil. If no profile currently is associated with username, Load returns
-// ErrNoSuchProfile. If an error is returned, p will be nil.
-func Load(ctx context.Context, username string) (p *Profile, err error) {
+// ErrNoSuchProfile. If username is malformed, an *InvalidUsernameError is
+// returned without contacting the Mojang servers. If an error is returned, p
+// will be nil.
+func (c *Client) Load(ctx context.Context, username string) (p *Profile, err error) {
 	if username == "" {
 		return nil, ErrNoSuchProfile
 	}
-	endpoint := fmt.Sprintf(loadURL, username)
-	return loadByName(ctx, endpoint)
+	if err = c.validateUsername(username); err != nil {
+		return nil, err
+	}
+	endpoint := c.lookupURL(loadPath, servicesLoadPath, url.PathEscape(username))
+	return c.loadProfile(ctx, endpoint, nameKey(username), c.Endpoints == ServicesEndpoints)
 }
 
 // LoadAtTime fetches the profile associated with username at the specified
 // instant of time. ctx must be non-nil. If no profile was associated with
 // username at the specified instant of time, LoadAtTime returns
-// ErrNoSuchProfile. If an error is returned, p will be nil.
-func LoadAtTime(ctx context.Context, username string, t time.Time) (p *Profile, err error) {
+// ErrNoSuchProfile. If username is malformed, an *InvalidUsernameError is
+// returned without contacting the Mojang servers. If an error is returned, p
+// will be nil.
+func (c *Client) LoadAtTime(ctx context.Context, username string, t time.Time) (p *Profile, err error) {
 	if username == "" {
 		return nil, ErrNoSuchProfile
 	}
-	endpoint := fmt.Sprintf(loadAtTimeURL, username, t.Unix())
-	return loadByName(ctx, endpoint)
+	if err = c.validateUsername(username); err != nil {
+		return nil, err
+	}
+	endpoint := c.apiURL(loadAtTimePath, url.PathEscape(username), t.Unix())
+	return c.loadProfile(ctx, endpoint, "", false)
 }
 
-// Common implementation used by Load and LoadAtTime.
-func loadByName(ctx context.Context, endpoint string) (p *Profile, err error) {
-	js, err := internal.FetchJSON(ctx, client, endpoint)
-	if err != nil {
-		return nil, transformError(err)
-	}
+// Common implementation used by Load, LoadAtTime and LoadByID.
+// If key != "", the response is cached under key. services must be true if
+// endpoint is a Minecraft Services lookup, which reports unknown profiles by
+// responding 404.
+func (c *Client) loadProfile(ctx context.Context, endpoint, key string, services bool) (p *Profile, err error) {
+	js, hit := c.cached(key)
+	if !hit {
+		ctx, cancel := c.context(ctx)
+		defer cancel()
 
-	defer func() { // If JSON data isn't structured as expected
-		if r := recover(); r != nil {
-			p = nil
-			err = &url.Error{Op: "Parse", URL: endpoint, Err: internal.ErrUnknownFormat}
+		js, err = c.apiServer().FetchJSON(ctx, endpoint)
+		if err != nil {
+			if services {
+				return nil, transformLookupError(err)
+			}
+			return nil, transformError(err)
 		}
-	}()
+	}
 
+	var pj profileJSON
+	if err = internal.Decode(js, &pj); err != nil {
+		return nil, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+	}
 	p = &Profile{}
-	if !fillProfile(p, js.(map[string]interface{})) {
+	ok, err := fillProfile(p, &pj)
+	if err != nil {
+		return nil, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+	}
+	if !hit {
+		c.cache(key, js, c.cacheTTL())
+	}
+	if !ok {
 		return nil, ErrNoSuchProfile
 	}
 
+	if !hit {
+		c.observe(p)
+	}
 	return p, nil
 }
 
-// LoadByID fetches the profile identified by id. ctx must be non-nil. If no
-// profile is identified by id, LoadByID returns ErrNoSuchProfile. If an error
-// is returned, p will be nil.
-func LoadByID(ctx context.Context, id string) (p *Profile, err error) {
-	return LoadWithNameHistory(ctx, id)
+// LoadByID fetches the profile identified by id, which may be given in any
+// form accepted by ParseID. ctx must be non-nil. If no profile is identified
+// by id, LoadByID returns ErrNoSuchProfile. If id is malformed, an
+// *InvalidIDError is returned. If an error is returned, p will be nil.
+func (c *Client) LoadByID(ctx context.Context, id string) (p *Profile, err error) {
+	if id == "" {
+		return nil, ErrNoSuchProfile
+	}
+	pid, err := ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := c.lookupURL(loadByIDPath, servicesLoadByIDPath, url.PathEscape(string(pid)))
+	return c.loadProfile(ctx, endpoint, idKey(pid), c.Endpoints == ServicesEndpoints)
 }
 
 // LoadWithNameHistory fetches the profile identified by id, incl. its name
-// history. ctx must be non-nil. If no profile is identified by id,
-// LoadWithNameHistory returns ErrNoSuchProfile. If an error is returned,
-// p will be nil.
-func LoadWithNameHistory(ctx context.Context, id string) (p *Profile, err error) {
+// history. id may be given in any form accepted by ParseID. ctx must be
+// non-nil. If no profile is identified by id, LoadWithNameHistory returns
+// ErrNoSuchProfile. If id is malformed, an *InvalidIDError is returned.
+// If an error is returned, p will be nil.
+//
+// The profile is loaded like LoadByID, while its name history is loaded from
+// c.HistorySource. If c has no HistorySource, ErrNameHistoryUnavailable is
+// returned without contacting the Mojang servers.
+func (c *Client) LoadWithNameHistory(ctx context.Context, id string) (p *Profile, err error) {
 	if id == "" {
 		return nil, ErrNoSuchProfile
 	}
-	pr := Profile{ID: id}
-	_, err = pr.LoadNameHistory(ctx, true)
+	pid, err := ParseID(id)
 	if err != nil {
 		return nil, err
 	}
-	return &pr, nil
+	if c.HistorySource == nil {
+		return nil, ErrNameHistoryUnavailable
+	}
+	if p, err = c.LoadByID(ctx, string(pid)); err != nil {
+		return nil, err
+	}
+	name := p.Name
+	if _, err = c.LoadNameHistory(ctx, p, false); err != nil {
+		return nil, err
+	}
+	p.Name = name // The live username takes precedence over the history source.
+	return p, nil
 }
 
 // LoadWithProperties fetches the profile identified by id, incl. its
-// properties. ctx must be non-nil. If no profile is identified by id,
-// LoadWithProperties returns ErrNoSuchProfile. If an error is returned,
-// p will be nil.
+// properties. id may be given in any form accepted by ParseID. ctx must be
+// non-nil. If no profile is identified by id, LoadWithProperties returns
+// ErrNoSuchProfile. If id is malformed, an *InvalidIDError is returned.
+// If an error is returned, p will be nil.
 //
 // NB! For each profile, profile properties may only be requested once per
 // minute.
-func LoadWithProperties(ctx context.Context, id string) (p *Profile, err error) {
+func (c *Client) LoadWithProperties(ctx context.Context, id string) (p *Profile, err error) {
 	if id == "" {
 		return nil, ErrNoSuchProfile
 	}
-	pr := Profile{ID: id}
-	_, err = pr.LoadProperties(ctx, true)
+	pid, err := ParseID(id)
+	if err != nil {
+		return nil, err
+	}
+	pr := Profile{ID: pid}
+	_, err = c.LoadProperties(ctx, &pr, false)
 	if err != nil {
 		return nil, err
 	}
@@ -107,66 +203,146 @@ func LoadWithProperties(ctx context.Context, id string) (p *Profile, err error)
 // returned results. Duplicate usernames are only returned once, and ps will be
 // nil if an error occurs. ctx must be non-nil.
 //
+// Empty usernames are ignored. If any other username is malformed, an
+// *InvalidUsernameError is returned without contacting the Mojang servers.
+//
 // NB! Only a maximum of LoadManyMaxSize profiles may be fetched at once.
 // If more are attempted loaded in the same operation, an ErrMaxSizeExceeded
 // error is returned.
-func LoadMany(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
+func (c *Client) LoadMany(ctx context.Context, usernames ...string) (ps []*Profile, err error) {
 	if len(usernames) > LoadManyMaxSize {
 		return nil, ErrMaxSizeExceeded{len(usernames)}
 	}
+	for _, u := range usernames {
+		if u == "" {
+			continue
+		}
+		if err = c.validateUsername(u); err != nil {
+			return nil, err
+		}
+	}
+	ps, _, err = c.loadMany(ctx, usernames)
+	return ps, err
+}
+
+// loadMany implements LoadMany, assuming usernames have been validated.
+// In addition to the profiles loaded, it returns the set of lower-cased
+// usernames which belong to demo profiles.
+func (c *Client) loadMany(ctx context.Context, usernames []string) (ps []*Profile, demos map[string]bool, err error) {
+	ps = make([]*Profile, 0, len(usernames))
+	demos = make(map[string]bool)
+	seen := make(map[string]bool, len(usernames))
 
-	c := 0
+	n := 0
 	var users [LoadManyMaxSize]string
 	for _, u := range usernames {
 		// Remove empty usernames. They are not accepted by the Mojang API.
-		if u != "" {
-			users[c] = u
-			c++
+		if u == "" {
+			continue
 		}
+		key := nameKey(u)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if js, ok := c.cached(key); ok {
+			if p, ok := cachedProfile(js); ok {
+				if p != nil {
+					ps = append(ps, p)
+				} else { // Cached demo profiles are skipped
+					demos[strings.ToLower(u)] = true
+				}
+				continue
+			}
+		}
+		users[n] = u
+		n++
 	}
 
-	if c == 0 {
-		return nil, nil // No need to request anything
+	if n == 0 {
+		if len(ps) == 0 {
+			return nil, demos, nil // No need to request anything
+		}
+		return ps, demos, nil
 	}
 
-	js, err := internal.ExchangeJSON(ctx, client, loadManyURL, users[:c])
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	endpoint := c.lookupURL(loadManyPath, servicesLoadManyPath)
+	js, err := c.apiServer().ExchangeJSON(ctx, endpoint, users[:n])
 	if err != nil {
-		return nil, transformError(err)
+		return nil, nil, transformError(err)
 	}
 
-	defer func() { // If JSON data isn't structured as expected
-		if r := recover(); r != nil {
-			err = &url.Error{Op: "Parse", URL: loadManyURL, Err: internal.ErrUnknownFormat}
-			ps = nil
-		}
-	}()
-
-	arr := js.([]interface{})
-	ps = make([]*Profile, 0, len(arr))
+	var arr []profileJSON
+	if err = internal.Decode(js, &arr); err != nil {
+		return nil, nil, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+	}
 
 	var pr *Profile
-	for _, p := range arr {
+	for i := range arr {
+		pj := &arr[i]
 		if pr == nil {
 			pr = &Profile{} // Reuse allocation of skipped demo profile
 		}
-		if !fillProfile(pr, p.(map[string]interface{})) {
+		ok, err := fillProfile(pr, pj)
+		if err != nil {
+			return nil, nil, &url.Error{Op: "Parse", URL: endpoint, Err: internal.AtPath(err, strconv.Itoa(i))}
+		}
+		if c.Cache != nil && pj.Name != "" { // Don't build keys in vain
+			c.cache(nameKey(pj.Name), pj, c.cacheTTL())
+		}
+		if !ok {
+			if pj.Name != "" {
+				demos[strings.ToLower(pj.Name)] = true
+			}
 			continue
 		}
+		c.observe(pr)
 		ps = append(ps, pr)
 		pr = nil
 	}
-	return ps, nil
+	return ps, demos, nil
 }
 
-var client = &http.Client{}
+// cachedProfile builds a profile from a cached lookup by username. p is nil
+// if js represents a demo profile. ok is false if js isn't structured as
+// expected.
+func cachedProfile(js json.RawMessage) (p *Profile, ok bool) {
+	var pj profileJSON
+	if internal.Decode(js, &pj) != nil {
+		return nil, false
+	}
+	p = &Profile{}
+	if found, err := fillProfile(p, &pj); err != nil {
+		return nil, false
+	} else if !found {
+		return nil, true
+	}
+	return p, true
+}
 
 func transformError(src error) error {
 	if e, ok := internal.UnwrapFailedRequestError(src); ok {
 		if e.StatusCode == 204 {
 			return ErrNoSuchProfile
-		} else if e.ErrorCode == "TooManyRequestsException" {
+		} else if e.StatusCode == 429 || e.ErrorCode == "TooManyRequestsException" {
 			return ErrTooManyRequests
 		}
 	}
 	return src
 }
+
+// transformLookupError is like transformError, but also handles the 404
+// response with which the Minecraft Services API reports that no profile
+// matches a lookup by username or ID, instead of responding 204. It must only
+// be used for Minecraft Services lookups, as other 404 responses e.g. stem from
+// misconfigured URLs.
+func transformLookupError(src error) error {
+	if e, ok := internal.UnwrapFailedRequestError(src); ok && e.StatusCode == 404 {
+		return ErrNoSuchProfile
+	}
+	return transformError(src)
+}
@@ -3,6 +3,7 @@ package profile
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -24,6 +25,12 @@ var testLoadInput = [...]struct {
 		expProfile: nil,
 		expErr:     ErrNoSuchProfile,
 	},
+	{
+		username:   "not/valid",
+		transport:  errorTransport{testError},
+		expProfile: nil,
+		expErr:     &InvalidUsernameError{Username: "not/valid"},
+	},
 	{
 		username: "doesNotExist",
 		transport: errorTransport{
@@ -36,23 +43,23 @@ var testLoadInput = [...]struct {
 	},
 	{
 		username:   "demoAccount",
-		transport:  http.NewFileTransport(http.Dir("testdata")),
+		transport:  testdataTransport,
 		expProfile: nil,
 		expErr:     ErrNoSuchProfile,
 	},
 	{
 		username:   "unexpectedFormat",
-		transport:  http.NewFileTransport(http.Dir("testdata")),
+		transport:  testdataTransport,
 		expProfile: nil,
 		expErr: &url.Error{
 			Op:  "Parse",
 			URL: "https://api.mojang.com/users/profiles/minecraft/unexpectedFormat",
-			Err: internal.ErrUnknownFormat,
+			Err: &internal.FormatError{Path: "id", Expected: "string", Actual: "missing"},
 		},
 	},
 	{
 		username:  "nergalic",
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -62,12 +69,11 @@ var testLoadInput = [...]struct {
 }
 
 func TestLoad(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testLoadInput {
-		client.Transport = tc.transport
-		profile, err := Load(context.Background(), tc.username)
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
+		profile, err := c.Load(context.Background(), tc.username)
 		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"Load(ctx, %q)\n"+
@@ -82,14 +88,13 @@ func TestLoad(t *testing.T) {
 }
 
 func TestLoadContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
-	Load(ctx, "nergalic")
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
+	c.Load(ctx, "nergalic")
 
 	if ct.Context != ctx {
 		t.Error("Load(ctx, \"nergalic\") didn't pass context to underlying http.Client")
@@ -110,6 +115,13 @@ var testLoadAtTimeInput = [...]struct {
 		expProfile: nil,
 		expErr:     ErrNoSuchProfile,
 	},
+	{
+		username:   "legacy-name",
+		time:       time.Unix(0, 0),
+		transport:  errorTransport{testError},
+		expProfile: nil,
+		expErr:     &InvalidUsernameError{Username: "legacy-name"},
+	},
 	{
 		username: "doesNotExist",
 		time:     time.Unix(0, 0),
@@ -124,23 +136,22 @@ var testLoadAtTimeInput = [...]struct {
 	{
 		username:   "unexpectedFormat",
 		time:       time.Unix(1337, 564),
-		transport:  http.NewFileTransport(http.Dir("testdata")),
+		transport:  testdataTransport,
 		expProfile: nil,
 		expErr: &url.Error{
 			Op:  "Parse",
 			URL: "https://api.mojang.com/users/profiles/minecraft/unexpectedFormat?at=1337",
-			Err: internal.ErrUnknownFormat,
+			Err: &internal.FormatError{Path: "id", Expected: "string", Actual: "missing"},
 		},
 	},
 }
 
 func TestLoadAtTime(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testLoadAtTimeInput {
-		client.Transport = tc.transport
-		profile, err := LoadAtTime(context.Background(), tc.username, tc.time)
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
+		profile, err := c.LoadAtTime(context.Background(), tc.username, tc.time)
 		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"LoadAtTime(ctx, %q, %s)\n"+
@@ -155,29 +166,102 @@ func TestLoadAtTime(t *testing.T) {
 }
 
 func TestLoadAtTimeContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
-	LoadAtTime(ctx, "nergalic", time.Now())
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
+	c.LoadAtTime(ctx, "nergalic", time.Now())
 
 	if ct.Context != ctx {
 		t.Error("LoadAtTime(ctx, \"nergalic\", time.Now()) didn't pass context to underlying http.Client")
 	}
 }
 
+var testLoadByIDInput = [...]struct {
+	id         string
+	transport  http.RoundTripper
+	expProfile *Profile
+	expErr     error
+}{
+	{
+		id:         "",
+		transport:  nil,
+		expProfile: nil,
+		expErr:     ErrNoSuchProfile,
+	},
+	{
+		id:         "not-an-id",
+		transport:  nil,
+		expProfile: nil,
+		expErr:     &InvalidIDError{ID: "not-an-id"},
+	},
+	{
+		id: "00000000000000000000000000000000", // Doesn't exist
+		transport: errorTransport{
+			&internal.FailedRequestError{
+				StatusCode: 204,
+			},
+		},
+		expProfile: nil,
+		expErr:     ErrNoSuchProfile,
+	},
+	{
+		id:        "087CC153-C343-4FF7-AC49-7DE1569AFFA1",
+		transport: testdataTransport,
+		expProfile: &Profile{
+			Name: "Nergalic",
+			ID:   "087cc153c3434ff7ac497de1569affa1",
+		},
+		expErr: nil,
+	},
+}
+
+func TestLoadByID(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range testLoadByIDInput {
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
+		profile, err := c.LoadByID(context.Background(), tc.id)
+		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf(
+				"LoadByID(ctx, %q)\n"+
+					" was: %#v, %s\n"+
+					"want: %#v, %s",
+				tc.id,
+				profile, p(err),
+				tc.expProfile, p(tc.expErr),
+			)
+		}
+	}
+}
+
+func TestLoadByIDContextUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), dummy, nil)
+	ct := CtxStoreTransport{}
+
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
+	c.LoadByID(ctx, "087cc153c3434ff7ac497de1569affa1")
+
+	if ct.Context != ctx {
+		t.Error("LoadByID(ctx, \"087cc153c3434ff7ac497de1569affa1\") didn't pass context to underlying http.Client")
+	}
+}
+
 var testLoadWithNameHistoryInput = [...]struct {
 	id         string
 	transport  http.RoundTripper
+	source     bool // Whether to use LegacyNameHistory as history source
 	expProfile *Profile
 	expErr     error
 }{
 	{
 		id:         "",
 		transport:  nil,
+		source:     true,
 		expProfile: nil,
 		expErr:     ErrNoSuchProfile,
 	},
@@ -188,12 +272,14 @@ var testLoadWithNameHistoryInput = [...]struct {
 				StatusCode: 204,
 			},
 		},
+		source:     true,
 		expProfile: nil,
 		expErr:     ErrNoSuchProfile,
 	},
 	{
 		id:        "087cc153c3434ff7ac497de1569affa1",
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
+		source:    true,
 		expProfile: &Profile{
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -206,21 +292,37 @@ var testLoadWithNameHistoryInput = [...]struct {
 		},
 		expErr: nil,
 	},
+	{
+		id:         "087cc153c3434ff7ac497de1569affa1",
+		transport:  errorTransport{testError}, // Mojang mustn't be contacted
+		source:     false,
+		expProfile: nil,
+		expErr:     ErrNameHistoryUnavailable,
+	},
+	{
+		id:         "not-an-id",
+		transport:  nil,
+		source:     false,
+		expProfile: nil,
+		expErr:     &InvalidIDError{ID: "not-an-id"},
+	},
 }
 
 func TestLoadWithNameHistory(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testLoadWithNameHistoryInput {
-		client.Transport = tc.transport
-		profile, err := LoadByID(context.Background(), tc.id) // Wrapper method used to test that as well
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
+		if tc.source {
+			c.HistorySource = LegacyNameHistory{Client: c}
+		}
+		profile, err := c.LoadWithNameHistory(context.Background(), tc.id)
 		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
-				"LoadWithNameHistory(ctx, %q)\n"+
+				"LoadWithNameHistory(ctx, %q) with history source: %t\n"+
 					" was: %#v, %s\n"+
 					"want: %#v, %s",
-				tc.id,
+				tc.id, tc.source,
 				profile, p(err),
 				tc.expProfile, p(tc.expErr),
 			)
@@ -228,18 +330,22 @@ func TestLoadWithNameHistory(t *testing.T) {
 	}
 }
 
-func TestLoadWithNameHistoryContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+func TestLoadWithNameHistoryLiveName(t *testing.T) {
+	t.Parallel()
 
-	ctx := context.WithValue(context.Background(), dummy, nil)
-	ct := CtxStoreTransport{}
-
-	client.Transport = &ct
-	LoadByID(ctx, "dummy") // Wrapper method used to test that as well
+	src := &fakeHistorySource{name: "OutdatedName", hist: []PastName{{Name: "GeneralSezuan", Until: msToTime(1423047705000)}}}
+	c := &Client{
+		HTTPClient:    &http.Client{Transport: testdataTransport},
+		HistorySource: src,
+	}
+	exp := &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic", NameHistory: src.hist}
 
-	if ct.Context != ctx {
-		t.Error("LoadWithNameHistory(ctx, \"dummy\") didn't pass context to underlying http.Client")
+	pr, err := c.LoadWithNameHistory(context.Background(), "087cc153c3434ff7ac497de1569affa1")
+	if !reflect.DeepEqual(pr, exp) || err != nil {
+		t.Errorf("LoadWithNameHistory(ctx, \"087cc153c3434ff7ac497de1569affa1\") was %#v, %s; want %#v, <nil>", pr, p(err), exp)
+	}
+	if src.id != "087cc153c3434ff7ac497de1569affa1" {
+		t.Errorf("LoadWithNameHistory(ctx, \"087cc153c3434ff7ac497de1569affa1\") requested history of %q", src.id)
 	}
 }
 
@@ -267,7 +373,32 @@ var testLoadWithPropertiesInput = [...]struct {
 	},
 	{
 		id:        "087cc153c3434ff7ac497de1569affa1",
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
+		expProfile: &Profile{
+			Name: "Nergalic",
+			ID:   "087cc153c3434ff7ac497de1569affa1",
+			Properties: &Properties{
+				SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+				CapeURL: "",
+				Model:   Steve,
+				Textures: &Textures{
+					Timestamp:   msToTime(1495799175553),
+					ProfileID:   "087cc153c3434ff7ac497de1569affa1",
+					ProfileName: "Nergalic",
+					Textures: map[string]Texture{
+						SkinTexture: {
+							URL:  "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+							Hash: "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+						},
+					},
+				},
+			},
+		},
+		expErr: nil,
+	},
+	{
+		id:        "087CC153-C343-4FF7-AC49-7DE1569AFFA1", // Dashed and upper-case
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -275,25 +406,41 @@ var testLoadWithPropertiesInput = [...]struct {
 				SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 				CapeURL: "",
 				Model:   Steve,
+				Textures: &Textures{
+					Timestamp:   msToTime(1495799175553),
+					ProfileID:   "087cc153c3434ff7ac497de1569affa1",
+					ProfileName: "Nergalic",
+					Textures: map[string]Texture{
+						SkinTexture: {
+							URL:  "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+							Hash: "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+						},
+					},
+				},
 			},
 		},
 		expErr: nil,
 	},
 	{
-		id:         "fictiveDemo",
-		transport:  http.NewFileTransport(http.Dir("testdata")),
+		id:         "087cc153+c343+4ff7+ac49+7de1569affa1",
+		transport:  nil,
+		expProfile: nil,
+		expErr:     &InvalidIDError{ID: "087cc153+c343+4ff7+ac49+7de1569affa1"},
+	},
+	{
+		id:         "00000000000000000000000000000002", // fictiveDemo
+		transport:  testdataTransport,
 		expProfile: nil,
 		expErr:     ErrNoSuchProfile,
 	},
 }
 
 func TestLoadWithProperties(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testLoadWithPropertiesInput {
-		client.Transport = tc.transport
-		profile, err := LoadWithProperties(context.Background(), tc.id)
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
+		profile, err := c.LoadWithProperties(context.Background(), tc.id)
 		if !reflect.DeepEqual(profile, tc.expProfile) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"LoadWithProperties(ctx, %q)\n"+
@@ -308,17 +455,16 @@ func TestLoadWithProperties(t *testing.T) {
 }
 
 func TestLoadWithPropertiesContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
-	LoadWithProperties(ctx, "dummy")
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
+	c.LoadWithProperties(ctx, "087cc153c3434ff7ac497de1569affa1")
 
 	if ct.Context != ctx {
-		t.Error("LoadWithProperties(ctx, \"dummy\") didn't pass context to underlying http.Client")
+		t.Error("LoadWithProperties(ctx, \"087cc153c3434ff7ac497de1569affa1\") didn't pass context to underlying http.Client")
 	}
 }
 
@@ -346,6 +492,12 @@ var testLoadManyInput = [...]struct {
 		expProfiles: nil,
 		expErr:      ErrMaxSizeExceeded{LoadManyMaxSize + 1},
 	},
+	{
+		ids:         []string{"nergalic", "", "not valid"},
+		transport:   errorTransport{testError},
+		expProfiles: nil,
+		expErr:      &InvalidUsernameError{Username: "not valid"},
+	},
 	{
 		ids:         []string{"dummy"},
 		transport:   http.NewFileTransport(http.Dir("testdata/LoadMany/unexpectedFormat")),
@@ -353,7 +505,7 @@ var testLoadManyInput = [...]struct {
 		expErr: &url.Error{
 			Op:  "Parse",
 			URL: "https://api.mojang.com/profiles/minecraft",
-			Err: internal.ErrUnknownFormat,
+			Err: &internal.FormatError{Path: "", Expected: "array", Actual: "object"},
 		},
 	},
 	{
@@ -372,15 +524,29 @@ var testLoadManyInput = [...]struct {
 		},
 		expErr: nil,
 	},
+	{
+		ids:       []string{"nergalic", "BreeSakana"},
+		transport: testdataTransport,
+		expProfiles: []*Profile{
+			{
+				ID:   "d9a5b542ce88442aaab38ec13e6c7773",
+				Name: "BreeSakana",
+			},
+			{
+				ID:   "087cc153c3434ff7ac497de1569affa1",
+				Name: "Nergalic",
+			},
+		},
+		expErr: nil,
+	},
 }
 
 func TestLoadMany(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testLoadManyInput {
-		client.Transport = tc.transport
-		profiles, err := LoadMany(context.Background(), tc.ids...)
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
+		profiles, err := c.LoadMany(context.Background(), tc.ids...)
 		if !reflect.DeepEqual(profiles, tc.expProfiles) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"LoadMany(ctx, %q)\n"+
@@ -395,17 +561,40 @@ func TestLoadMany(t *testing.T) {
 }
 
 func TestLoadManyContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
-	LoadMany(ctx, "dummy")
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
+	c.LoadMany(ctx, "nergalic")
 
 	if ct.Context != ctx {
-		t.Error("LoadMany(ctx, \"dummy\") didn't pass context to underlying http.Client")
+		t.Error("LoadMany(ctx, \"nergalic\") didn't pass context to underlying http.Client")
+	}
+}
+
+func BenchmarkLoadMany(b *testing.B) {
+	usernames := make([]string, LoadManyMaxSize)
+	body := []byte{'['}
+	for i := range usernames {
+		usernames[i] = fmt.Sprintf("Player%03d", i)
+		if i > 0 {
+			body = append(body, ',')
+		}
+		body = append(body, fmt.Sprintf(`{"id":"%032x","name":%q}`, i+1, usernames[i])...)
+	}
+	body = append(body, ']')
+
+	c := &Client{HTTPClient: &http.Client{Transport: &staticTransport{status: 200, body: string(body)}}}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ps, err := c.LoadMany(ctx, usernames...); len(ps) != LoadManyMaxSize || err != nil {
+			b.Fatalf("LoadMany(ctx, <%d usernames>) was <%d profiles>, %s; want <%d profiles>, <nil>", LoadManyMaxSize, len(ps), p(err), LoadManyMaxSize)
+		}
 	}
 }
 
@@ -417,6 +606,15 @@ var dummy struct{}
 
 var testError = errors.New("testError")
 
+// testdataTransport serves the files in testdata. Unless testing against the
+// live API, DefaultClient uses it too, so table entries which use testdataTransport
+// or no transport at all also apply to the package-level functions.
+var testdataTransport = http.NewFileTransport(http.Dir("testdata"))
+
+func servedByDefaultClient(transport http.RoundTripper) bool {
+	return transport == nil || transport == testdataTransport
+}
+
 func p(x interface{}) interface{} {
 	if x == nil {
 		return "<nil>"
@@ -0,0 +1,156 @@
+package profile
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NameAt returns the username used by p at the time instant t, as recorded
+// by p.NameHistory and p.Name. ok is false if p.NameHistory hasn't been
+// loaded. Usernames are considered used since the profile was created or
+// the previous username stopped being used, until their Until instant.
+func (p *Profile) NameAt(t time.Time) (name string, ok bool) {
+	if p.NameHistory == nil {
+		return "", false
+	}
+	for i := len(p.NameHistory) - 1; i >= 0; i-- { // Oldest first
+		if past := p.NameHistory[i]; t.Before(past.Until) {
+			return past.Name, true
+		}
+	}
+	return p.Name, true
+}
+
+// An Ownership is an interval of time during which a profile owned a
+// username.
+type Ownership struct {
+	// Profile is the profile which owned the username, incl. its name
+	// history.
+	Profile *Profile
+	// From is when the profile took the username into use. It is zero if
+	// the profile was created with the username, or if it is unknown.
+	From time.Time
+	// Until is when the profile stopped using the username. It is zero if
+	// the profile still uses the username, or if it is unknown.
+	Until time.Time
+}
+
+// NameOwners is a wrapper around DefaultClient.NameOwners.
+func NameOwners(ctx context.Context, username string, at ...time.Time) (owns []Ownership, err error) {
+	return DefaultClient.NameOwners(ctx, username, at...)
+}
+
+// NameOwners lists the profiles which have owned username, along with when
+// they owned it, oldest first. A profile which has owned username several
+// times is listed once for each time. ctx must be non-nil.
+//
+// The owners are found using Load for the current owner, and LoadAtTime for
+// the original owner and the owners at each of the time instants given by
+// at, e.g. the times of chat messages to identify the senders of. The name
+// history of each owner found is then loaded to determine when it owned
+// username. Owners which weren't found this way aren't listed. If the name
+// history of an owner doesn't record that it owned username, which may be
+// the case for the approximate histories of a HistoryStore, it is listed
+// with From and Until both zero, ordered by the earliest instant it was
+// found to own username.
+//
+// Name histories are loaded from c.HistorySource, so if c has none,
+// ErrNameHistoryUnavailable is returned without contacting the Mojang
+// servers. If username is empty,
+// ErrNoSuchProfile is returned, and if it is malformed, an
+// *InvalidUsernameError is returned. If an error is returned, owns will be
+// nil.
+func (c *Client) NameOwners(ctx context.Context, username string, at ...time.Time) (owns []Ownership, err error) {
+	if c.HistorySource == nil {
+		return nil, ErrNameHistoryUnavailable
+	}
+	if username == "" {
+		return nil, ErrNoSuchProfile
+	}
+	if err = c.validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	var profiles []*Profile
+	found := make(map[ID]time.Time) // When each owner was found to own username
+	add := func(p *Profile, t time.Time, err error) error {
+		if err == ErrNoSuchProfile {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if prev, ok := found[p.ID]; !ok {
+			profiles = append(profiles, p)
+			found[p.ID] = t
+		} else if t.Before(prev) {
+			found[p.ID] = t
+		}
+		return nil
+	}
+
+	now := time.Now()
+	p, err := c.Load(ctx, username)
+	if err = add(p, now, err); err != nil {
+		return nil, err
+	}
+	for _, t := range append([]time.Time{time.Unix(0, 0)}, at...) {
+		p, err := c.LoadAtTime(ctx, username, t)
+		if err = add(p, t, err); err != nil {
+			return nil, err
+		}
+	}
+
+	var since []time.Time // When each ownership is known to have begun
+	for _, p := range profiles {
+		if _, err = c.LoadNameHistory(ctx, p, false); err != nil {
+			return nil, err
+		}
+		spans := ownerships(p, username)
+		if len(spans) == 0 {
+			owns = append(owns, Ownership{Profile: p}) // When is unknown
+			since = append(since, found[p.ID])
+			continue
+		}
+		for _, o := range spans {
+			owns = append(owns, o)
+			since = append(since, o.From)
+		}
+	}
+
+	sort.Stable(byTime{owns, since})
+	return owns, nil
+}
+
+// byTime sorts ownerships by the times in t, which are swapped along with
+// them.
+type byTime struct {
+	owns []Ownership
+	t    []time.Time
+}
+
+func (s byTime) Len() int           { return len(s.owns) }
+func (s byTime) Less(i, j int) bool { return s.t[i].Before(s.t[j]) }
+func (s byTime) Swap(i, j int) {
+	s.owns[i], s.owns[j] = s.owns[j], s.owns[i]
+	s.t[i], s.t[j] = s.t[j], s.t[i]
+}
+
+// ownerships returns the intervals during which p owned username according
+// to its name history, oldest first. Usernames are compared
+// case-insensitively.
+func ownerships(p *Profile, username string) (owns []Ownership) {
+	var from time.Time
+	for i := len(p.NameHistory) - 1; i >= 0; i-- { // Oldest first
+		past := p.NameHistory[i]
+		if strings.EqualFold(past.Name, username) {
+			owns = append(owns, Ownership{Profile: p, From: from, Until: past.Until})
+		}
+		from = past.Until
+	}
+	if strings.EqualFold(p.Name, username) {
+		owns = append(owns, Ownership{Profile: p, From: from})
+	}
+	return owns
+}
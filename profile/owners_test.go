@@ -0,0 +1,143 @@
+package profile
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNameAtInput = [...]struct {
+	t       time.Time
+	expName string
+}{
+	{time.Unix(0, 0), "GeneralSezuan"},
+	{msToTime(1423047704999), "GeneralSezuan"},
+	{msToTime(1423047705000), "Nergalic"},
+	{time.Now(), "Nergalic"},
+}
+
+func TestNameAt(t *testing.T) {
+	pr := &Profile{
+		ID:          "087cc153c3434ff7ac497de1569affa1",
+		Name:        "Nergalic",
+		NameHistory: []PastName{{Name: "GeneralSezuan", Until: msToTime(1423047705000)}},
+	}
+	for _, tc := range testNameAtInput {
+		if name, ok := pr.NameAt(tc.t); name != tc.expName || !ok {
+			t.Errorf("NameAt(%s) was %q, %t; want %q, true", tc.t, name, ok, tc.expName)
+		}
+	}
+
+	pr.NameHistory = nil
+	if name, ok := pr.NameAt(time.Now()); name != "" || ok {
+		t.Errorf("NameAt(<now>) without name history was %q, %t; want \"\", false", name, ok)
+	}
+}
+
+func TestNameOwners(t *testing.T) {
+	t.Parallel()
+
+	// Original owns "Shared" until renaming to "Original". "Current" renames
+	// to "Shared" later. "Unrecorded" is found at 1500 seconds, but its
+	// history doesn't record the username, so when it owned it is unknown.
+	rt := routeTransport{
+		"/users/profiles/minecraft/Shared":         `{"id":"00000000000000000000000000000002","name":"Shared"}`,
+		"/users/profiles/minecraft/Shared?at=0":    `{"id":"00000000000000000000000000000001","name":"Original"}`,
+		"/users/profiles/minecraft/Shared?at=1500": `{"id":"00000000000000000000000000000003","name":"Unrecorded"}`,
+	}
+	src := mapHistorySource{
+		"00000000000000000000000000000001": {{Name: "Shared", Until: time.Unix(1000, 0)}},
+		"00000000000000000000000000000002": {{Name: "Before", Until: time.Unix(2000, 0)}},
+		"00000000000000000000000000000003": {},
+	}
+	c := &Client{HTTPClient: &http.Client{Transport: rt}, HistorySource: src}
+
+	owns, err := c.NameOwners(context.Background(), "Shared", time.Unix(1500, 0), time.Unix(1600, 0))
+	if err != nil {
+		t.Fatalf("NameOwners(ctx, \"Shared\", ...) failed: %s", err)
+	}
+
+	exp := []struct {
+		id          ID
+		from, until time.Time
+	}{
+		{"00000000000000000000000000000001", time.Time{}, time.Unix(1000, 0)},
+		{"00000000000000000000000000000003", time.Time{}, time.Time{}},
+		{"00000000000000000000000000000002", time.Unix(2000, 0), time.Time{}},
+	}
+	if len(owns) != len(exp) {
+		t.Fatalf("NameOwners(ctx, \"Shared\", ...) returned %d ownerships; want %d", len(owns), len(exp))
+	}
+	for i, o := range owns {
+		if o.Profile.ID != exp[i].id || !o.From.Equal(exp[i].from) || !o.Until.Equal(exp[i].until) {
+			t.Errorf("NameOwners(ctx, \"Shared\", ...)[%d] was %s from %s until %s; want %s from %s until %s",
+				i, o.Profile.ID, o.From, o.Until, exp[i].id, exp[i].from, exp[i].until)
+		}
+		if o.Profile.NameHistory == nil {
+			t.Errorf("NameOwners(ctx, \"Shared\", ...)[%d] has no name history", i)
+		}
+	}
+}
+
+func TestNameOwnersErrors(t *testing.T) {
+	t.Parallel()
+
+	rt := &countingTransport{transport: routeTransport{"/users/profiles/minecraft/Shared": `{"id":"00000000000000000000000000000002","name":"Shared"}`}}
+	c := &Client{HTTPClient: &http.Client{Transport: rt}}
+
+	if owns, err := c.NameOwners(context.Background(), "Shared"); owns != nil || err != ErrNameHistoryUnavailable {
+		t.Errorf("NameOwners(ctx, \"Shared\") without history source was %v, %s; want nil, %s", owns, p(err), ErrNameHistoryUnavailable)
+	}
+	if n := rt.Count(); n != 0 {
+		t.Errorf("NameOwners(ctx, \"Shared\") without history source performed %d requests; want 0", n)
+	}
+
+	c.HistorySource = mapHistorySource{}
+	exp := &InvalidUsernameError{Username: "not valid"}
+	if owns, err := c.NameOwners(context.Background(), "not valid"); owns != nil || !reflect.DeepEqual(err, exp) {
+		t.Errorf("NameOwners(ctx, \"not valid\") was %v, %s; want nil, %s", owns, p(err), exp)
+	}
+	if owns, err := c.NameOwners(context.Background(), ""); owns != nil || err != ErrNoSuchProfile {
+		t.Errorf("NameOwners(ctx, \"\") was %v, %s; want nil, %s", owns, p(err), ErrNoSuchProfile)
+	}
+	if owns, err := c.NameOwners(context.Background(), "Unowned"); owns != nil || err != nil {
+		t.Errorf("NameOwners(ctx, \"Unowned\") was %v, %s; want nil, <nil>", owns, p(err))
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// routeTransport responds with the JSON body mapped to by the path and query
+// of each request, or with 204 No Content if none is.
+type routeTransport map[string]string
+
+func (rt routeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := rt[req.URL.RequestURI()]
+	status := 200
+	if !ok {
+		status = 204
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// mapHistorySource provides the past names mapped to by profile IDs.
+type mapHistorySource map[ID][]PastName
+
+func (s mapHistorySource) NameHistory(ctx context.Context, id ID) (string, []PastName, error) {
+	hist, ok := s[id]
+	if !ok {
+		return "", nil, ErrNameHistoryUnavailable
+	}
+	return "", hist, nil
+}
@@ -6,27 +6,30 @@
 // are returned or not, to ensure consistency this package have been written never
 // to return those.
 //
+// Mojang no longer serves the username history of profiles. To load name
+// histories, a Client must be configured with a NameHistorySource, such as a
+// HistoryStore recording the usernames observed by the Client.
+//
 // Please note that the public Mojang API is request rate limited, so if you expect
-// heavy usage you should cache the results.
+// heavy usage you should cache the results, e.g. by configuring a Client with a
+// Cache such as MemoryCache or DiskCache.
 // For more information on rate limits see the documentation for ErrTooManyRequests.
 package profile
 
 import (
 	"context"
+	"encoding/json"
 	"io"
-	"net/http"
 	"net/url"
 	"time"
 
-	"fmt"
-
 	"github.com/PhilipBorgesen/minecraft/internal"
 )
 
 // Profile represents the profile of a Minecraft user account.
 type Profile struct {
 	// ID is the profile's universally unique identifier, which never changes.
-	ID string
+	ID ID
 	// Name is the profile's currently associated username, subject to change.
 	Name string
 	// NameHistory is the profile's past usernames incl. when each username
@@ -47,42 +50,60 @@ func (p *Profile) String() string {
 
 // LoadNameHistory loads and returns p.NameHistory, which contains the
 // profile's past usernames. If force is true, p.NameHistory will be loaded
-// anew from the Mojang servers even though it already is present. If force
-// is false, p.NameHistory will only be loaded if nil.
+// anew even though it already is present. If force is false, p.NameHistory
+// will only be loaded if nil.
+//
+// Mojang no longer serves name histories, so they are loaded from the
+// HistorySource of the client. If it has none, or it has no history of the
+// profile, ErrNameHistoryUnavailable is returned.
 //
-// ctx must be non-nil and p.ID must be set. When the name history is loaded,
-// p.Name will also be updated if it has changed.
+// ctx must be non-nil and p.ID must be set to a valid ID. When the name
+// history is loaded, p.Name will also be updated if the history source
+// reports a different current username.
 //
 // No matter whether the loading succeeds or not, p.NameHistory will be
 // returned as hist, which thus only will be nil if the loading fails and
 // p.NameHistory was nil beforehand.
 //
 // A profile which was loaded by LoadWithNameHistory has p.NameHistory
-// pre-loaded.
+// pre-loaded. LoadNameHistory uses DefaultClient.
 func (p *Profile) LoadNameHistory(ctx context.Context, force bool) (hist []PastName, err error) {
+	return DefaultClient.LoadNameHistory(ctx, p, force)
+}
+
+// LoadNameHistory is like p.LoadNameHistory(ctx, force), except the name
+// history is loaded from c.HistorySource. If force is true, a history source
+// such as LegacyNameHistory won't serve the name history from its cache, but
+// only store the fresh response in it.
+func (c *Client) LoadNameHistory(ctx context.Context, p *Profile, force bool) (hist []PastName, err error) {
 	if p.NameHistory == nil || force {
 		if p.ID == "" {
 			return p.NameHistory, ErrUnsetPlayerID
 		}
+		var id ID
+		if id, err = ParseID(string(p.ID)); err != nil {
+			return p.NameHistory, err
+		}
+		if c.HistorySource == nil {
+			return p.NameHistory, ErrNameHistoryUnavailable
+		}
 
-		var js interface{}
-		endpoint := fmt.Sprintf(loadWithNameHistoryURL, p.ID)
-
-		js, err = internal.FetchJSON(ctx, client, endpoint)
+		var name string
+		if fs, ok := c.HistorySource.(forcedHistorySource); ok {
+			name, hist, err = fs.nameHistory(ctx, id, force)
+		} else {
+			name, hist, err = c.HistorySource.NameHistory(ctx, id)
+		}
 		if err != nil {
-			return p.NameHistory, transformError(err)
+			return p.NameHistory, err
 		}
 
-		defer func() { // If JSON data isn't structured as expected
-			if r := recover(); r != nil {
-				hist = p.NameHistory
-				err = &url.Error{Op: "Parse", URL: endpoint, Err: internal.ErrUnknownFormat}
-			}
-		}()
-
-		name, hist := buildHistory(js.([]interface{}))
-
-		p.Name = name
+		if hist == nil {
+			hist = emptyHist // Loaded, but no past usernames
+		}
+		if name != "" {
+			p.Name = name
+		}
 		p.NameHistory = hist
 	}
 	return p.NameHistory, nil
@@ -93,48 +114,84 @@ func (p *Profile) LoadNameHistory(ctx context.Context, force bool) (hist []PastN
 // from the Mojang servers even though it already is present. If force is
 // false, p.Properties will only be loaded if nil.
 //
-// ctx must be non-nil and p.ID must be set. When properties are loaded, p.Name
-// will also be updated if it has changed.
+// ctx must be non-nil and p.ID must be set to a valid ID. When properties are
+// loaded, p.Name will also be updated if it has changed.
 //
 // No matter whether the loading succeeds or not, p.Properties will be returned
 // as ps, which thus only will be nil if the loading fails and p.Properties was
 // nil beforehand.
 //
 // A profile which was loaded by LoadWithProperties has p.Properties pre-loaded.
+// LoadProperties uses DefaultClient.
 //
 // NB! For each profile, profile properties may only be requested once per minute.
 func (p *Profile) LoadProperties(ctx context.Context, force bool) (ps *Properties, err error) {
+	return DefaultClient.LoadProperties(ctx, p, force)
+}
+
+// LoadProperties is like p.LoadProperties(ctx, force), except c is used to
+// load the properties. If force is true, the properties aren't read from
+// c.Cache, but the fresh response is stored in it.
+//
+// NB! For each profile, profile properties may only be requested once per minute.
+func (c *Client) LoadProperties(ctx context.Context, p *Profile, force bool) (ps *Properties, err error) {
 	if p.Properties == nil || force {
 		if p.ID == "" {
 			return p.Properties, ErrUnsetPlayerID
 		}
+		var id ID
+		if id, err = ParseID(string(p.ID)); err != nil {
+			return p.Properties, err
+		}
 
-		var js interface{}
-		endpoint := fmt.Sprintf(loadWithPropertiesURL, p.ID)
+		key := propertiesKey(id)
+		endpoint := c.sessionURL(loadWithPropertiesPath, url.PathEscape(string(id)))
+		if c.SignedProperties {
+			key = signedPropertiesKey(id)
+			endpoint = c.sessionURL(loadSignedPropertiesPath, url.PathEscape(string(id)))
+		}
 
-		js, err = internal.FetchJSON(ctx, client, endpoint)
-		if err != nil {
-			return p.Properties, transformError(err)
+		var js json.RawMessage
+		var hit bool
+		if !force {
+			js, hit = c.cached(key)
 		}
+		if !hit {
+			ctx, cancel := c.context(ctx)
+			defer cancel()
 
-		defer func() { // If JSON data isn't structured as expected
-			if r := recover(); r != nil {
-				ps = p.Properties
-				err = &url.Error{Op: "Parse", URL: endpoint, Err: internal.ErrUnknownFormat}
+			js, err = c.sessionServer(id).FetchJSON(ctx, endpoint)
+			if err != nil {
+				return p.Properties, transformError(err)
 			}
-		}()
+		}
 
-		m := js.(map[string]interface{})
-		ps, err = buildProperties(m["properties"].([]interface{}))
+		var pj profileJSON
+		if err = internal.Decode(js, &pj); err != nil {
+			return p.Properties, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+		}
+		if pj.Properties == nil {
+			return p.Properties, &url.Error{Op: "Parse", URL: endpoint, Err: internal.Missing("properties", "array")}
+		}
+		ps, err = buildProperties(pj.Properties, c.SignedProperties)
 		if err != nil {
 			// Let the entire loading fail even if just property construction fails.
 			// May always be changed later if this is too drastic.
-			return p.Properties, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+			return p.Properties, &url.Error{Op: "Parse", URL: endpoint, Err: internal.AtPath(err, "properties")}
 		}
 
-		if !fillProfile(p, m) {
+		if !hit {
+			c.cache(key, js, c.propertiesTTL())
+		}
+
+		if found, err := fillProfile(p, &pj); err != nil {
+			return p.Properties, &url.Error{Op: "Parse", URL: endpoint, Err: err}
+		} else if !found {
 			return p.Properties, ErrNoSuchProfile
 		}
+		if !hit {
+			c.observe(p)
+		}
 
 		p.Properties = ps
 	}
@@ -186,6 +243,13 @@ type Properties struct {
 	CapeURL string
 	// Model is the profile's player model type.
 	Model Model
+	// Textures is the full textures property from which SkinURL, CapeURL
+	// and Model are derived. nil if the profile has no textures property.
+	Textures *Textures
+	// Raw holds the properties as received from the Mojang servers, incl.
+	// their signatures. Raw is only set when the properties were loaded by
+	// a Client with SignedProperties set.
+	Raw []Property
 
 	_ struct{} // Ensure Properties is constructed using named parameters.
 }
@@ -195,16 +259,25 @@ type Properties struct {
 // p.Model will be attempted to be retrieved instead.
 //
 // It is the client's responsibility to close the ReadCloser. When an error is
-// returned, ReadCloser is nil.
+// returned, ReadCloser is nil. SkinReader uses DefaultClient.
 func (p *Properties) SkinReader(ctx context.Context) (io.ReadCloser, error) {
+	return DefaultClient.SkinReader(ctx, p)
+}
+
+// SkinReader is like p.SkinReader(ctx), except c is used to retrieve the
+// skin texture.
+// The texture is downloaded on every call; see TextureStore for a reader
+// which only downloads each texture once.
+func (c *Client) SkinReader(ctx context.Context, p *Properties) (io.ReadCloser, error) {
 	url := p.SkinURL
 	if url == "" {
-		url = p.Model.defaultSkinURL()
-		if url == "" {
+		path := p.Model.defaultSkinPath()
+		if path == "" {
 			return nil, ErrUnknownModel
 		}
+		url = c.assetsURL(path)
 	}
-	return loadTexture(ctx, url)
+	return c.loadTexture(ctx, url)
 }
 
 // CapeReader is a convenience method for retrieving the cape texture at
@@ -212,36 +285,31 @@ func (p *Properties) SkinReader(ctx context.Context) (io.ReadCloser, error) {
 // error.
 //
 // It is the client's responsibility to close the ReadCloser. When an error is
-// returned, ReadCloser is nil.
+// returned, ReadCloser is nil. CapeReader uses DefaultClient.
 func (p *Properties) CapeReader(ctx context.Context) (io.ReadCloser, error) {
+	return DefaultClient.CapeReader(ctx, p)
+}
+
+// CapeReader is like p.CapeReader(ctx), except c is used to retrieve the
+// cape texture.
+// The texture is downloaded on every call; see TextureStore for a reader
+// which only downloads each texture once.
+func (c *Client) CapeReader(ctx context.Context, p *Properties) (io.ReadCloser, error) {
 	if p.CapeURL == "" {
 		return nil, ErrNoCape
 	}
-	return loadTexture(ctx, p.CapeURL)
+	return c.loadTexture(ctx, p.CapeURL)
 }
 
-func loadTexture(ctx context.Context, endpoint string) (io.ReadCloser, error) {
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
+func (c *Client) loadTexture(ctx context.Context, endpoint string) (io.ReadCloser, error) {
+	ctx, cancel := c.context(ctx)
 
-	resp, err := client.Do(req)
+	r, err := c.server().Fetch(ctx, endpoint)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
-
-	if resp.StatusCode != 200 {
-		err = &url.Error{
-			Op:  "Get",
-			URL: endpoint,
-			Err: &internal.FailedRequestError{StatusCode: resp.StatusCode},
-		}
-		return nil, err
-	}
-
-	return resp.Body, nil
+	return cancelOnClose{r, cancel}, nil
 }
 
 // Model represents the player model type used by a profile.
@@ -267,13 +335,13 @@ func (m Model) String() string {
 	}
 }
 
-// defaultSkinURL returns a URL to the default skin of m
-func (m Model) defaultSkinURL() string {
+// defaultSkinPath returns the path to the default skin of m on the assets server.
+func (m Model) defaultSkinPath() string {
 	switch m {
 	case Steve:
-		return steveSkinURL
+		return steveSkinPath
 	case Alex:
-		return alexSkinURL
+		return alexSkinPath
 	default:
 		return ""
 	}
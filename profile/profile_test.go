@@ -144,7 +144,7 @@ var testProfileLoadNameHistoryInput = [...]struct {
 			ID: "087cc153c3434ff7ac497de1569affa1",
 		},
 		force:     false,
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -174,7 +174,7 @@ var testProfileLoadNameHistoryInput = [...]struct {
 			},
 		},
 		force:     false,
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -204,7 +204,7 @@ var testProfileLoadNameHistoryInput = [...]struct {
 			},
 		},
 		force:     true,
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -245,33 +245,33 @@ var testProfileLoadNameHistoryInput = [...]struct {
 	{ // Unforced: Old history returned (but not updated) on error
 		profile:    &Profile{ID: "00000000000000000000000000000000"}, // Doesn't exist
 		force:      false,
-		transport:  statusOverrideTransport{status: 204, transport: http.NewFileTransport(http.Dir("testdata"))},
+		transport:  statusOverrideTransport{status: 204, transport: testdataTransport},
 		expProfile: &Profile{ID: "00000000000000000000000000000000"},
 		expHist:    nil,
 		expErr:     ErrNoSuchProfile,
 	},
 	{ // Format error
-		profile:    &Profile{ID: "unexpectedFormat"},
-		transport:  http.NewFileTransport(http.Dir("testdata")),
-		expProfile: &Profile{ID: "unexpectedFormat"},
+		profile:    &Profile{ID: "00000000000000000000000000000001"},
+		transport:  testdataTransport,
+		expProfile: &Profile{ID: "00000000000000000000000000000001"},
 		expHist:    nil,
 		expErr: &url.Error{
 			Op:  "Parse",
-			URL: "https://api.mojang.com/user/profiles/unexpectedFormat/names",
-			Err: internal.ErrUnknownFormat,
+			URL: "https://api.mojang.com/user/profiles/00000000000000000000000000000001/names",
+			Err: &internal.FormatError{Path: "", Expected: "array", Actual: "object"},
 		},
 	},
 }
 
 func TestProfile_LoadNameHistory(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testProfileLoadNameHistoryInput {
-		client.Transport = tc.transport
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
+		c.HistorySource = LegacyNameHistory{Client: c}
 		profile := *tc.profile
 
-		hist, err := profile.LoadNameHistory(context.Background(), tc.force)
+		hist, err := c.LoadNameHistory(context.Background(), &profile, tc.force)
 		if !reflect.DeepEqual(&profile, tc.expProfile) || !reflect.DeepEqual(hist, tc.expHist) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"%#v.LoadNameHistory(ctx, %t) produced result:\n"+
@@ -286,19 +286,19 @@ func TestProfile_LoadNameHistory(t *testing.T) {
 }
 
 func TestProfile_LoadNameHistoryContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
+	c.HistorySource = LegacyNameHistory{Client: c}
 
-	profile := Profile{ID: "dummy"}
-	profile.LoadNameHistory(ctx, true)
+	profile := Profile{ID: "087cc153c3434ff7ac497de1569affa1"}
+	c.LoadNameHistory(ctx, &profile, true)
 
 	if ct.Context != ctx {
-		t.Error("Profile{ID: \"dummy\"}.LoadNameHistory(ctx, true) didn't pass context to underlying http.Client")
+		t.Error("Profile{ID: \"087cc153c3434ff7ac497de1569affa1\"}.LoadNameHistory(ctx, true) didn't pass context to underlying http.Client")
 	}
 }
 
@@ -315,7 +315,7 @@ var testProfileLoadPropertiesInput = [...]struct {
 			ID: "087cc153c3434ff7ac497de1569affa1",
 		},
 		force:     false,
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -323,12 +323,34 @@ var testProfileLoadPropertiesInput = [...]struct {
 				SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 				CapeURL: "",
 				Model:   Steve,
+				Textures: &Textures{
+					Timestamp:   msToTime(1495799175553),
+					ProfileID:   "087cc153c3434ff7ac497de1569affa1",
+					ProfileName: "Nergalic",
+					Textures: map[string]Texture{
+						SkinTexture: {
+							URL:  "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+							Hash: "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+						},
+					},
+				},
 			},
 		},
 		expProps: &Properties{
 			SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 			CapeURL: "",
 			Model:   Steve,
+			Textures: &Textures{
+				Timestamp:   msToTime(1495799175553),
+				ProfileID:   "087cc153c3434ff7ac497de1569affa1",
+				ProfileName: "Nergalic",
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:  "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+						Hash: "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+					},
+				},
+			},
 		},
 		expErr: nil,
 	},
@@ -342,7 +364,7 @@ var testProfileLoadPropertiesInput = [...]struct {
 			},
 		},
 		force:     false,
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -369,7 +391,7 @@ var testProfileLoadPropertiesInput = [...]struct {
 			},
 		},
 		force:     true,
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expProfile: &Profile{
 			Name: "Nergalic",
 			ID:   "087cc153c3434ff7ac497de1569affa1",
@@ -377,12 +399,34 @@ var testProfileLoadPropertiesInput = [...]struct {
 				SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 				CapeURL: "",
 				Model:   Steve,
+				Textures: &Textures{
+					Timestamp:   msToTime(1495799175553),
+					ProfileID:   "087cc153c3434ff7ac497de1569affa1",
+					ProfileName: "Nergalic",
+					Textures: map[string]Texture{
+						SkinTexture: {
+							URL:  "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+							Hash: "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+						},
+					},
+				},
 			},
 		},
 		expProps: &Properties{
 			SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 			CapeURL: "",
 			Model:   Steve,
+			Textures: &Textures{
+				Timestamp:   msToTime(1495799175553),
+				ProfileID:   "087cc153c3434ff7ac497de1569affa1",
+				ProfileName: "Nergalic",
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:  "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+						Hash: "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+					},
+				},
+			},
 		},
 		expErr: nil,
 	},
@@ -412,56 +456,55 @@ var testProfileLoadPropertiesInput = [...]struct {
 		expErr: ErrUnsetPlayerID,
 	},
 	{ // Unforced: Old properties returned (but not updated) on error
-		profile:    &Profile{ID: "fictiveDemo"}, // Doesn't exist
+		profile:    &Profile{ID: "00000000000000000000000000000002"}, // Demo profile
 		force:      false,
-		transport:  http.NewFileTransport(http.Dir("testdata")),
-		expProfile: &Profile{ID: "fictiveDemo"},
+		transport:  testdataTransport,
+		expProfile: &Profile{ID: "00000000000000000000000000000002"},
 		expProps:   nil,
 		expErr:     ErrNoSuchProfile,
 	},
-	{
-		profile:    &Profile{ID: "noSkinAndBadUUID"},
-		transport:  http.NewFileTransport(http.Dir("testdata")),
-		expProfile: &Profile{ID: "noSkinAndBadUUID"},
+	{ // No skin and bad profile ID in textures
+		profile:    &Profile{ID: "00000000000000000000000000000003"},
+		transport:  testdataTransport,
+		expProfile: &Profile{ID: "00000000000000000000000000000003"},
 		expProps:   nil,
 		expErr: &url.Error{
 			Op:  "Parse",
-			URL: "https://sessionserver.mojang.com/session/minecraft/profile/noSkinAndBadUUID",
-			Err: internal.ErrUnknownFormat,
+			URL: "https://sessionserver.mojang.com/session/minecraft/profile/00000000000000000000000000000003",
+			Err: &internal.FormatError{Path: "properties.0.value.profileId", Expected: "profile ID", Actual: `"!BAD_ID!f3fd461daff5086b22154bce"`},
 		},
 	},
-	{
-		profile:    &Profile{ID: "badProperties"},
-		transport:  http.NewFileTransport(http.Dir("testdata")),
-		expProfile: &Profile{ID: "badProperties"},
+	{ // Bad properties
+		profile:    &Profile{ID: "00000000000000000000000000000004"},
+		transport:  testdataTransport,
+		expProfile: &Profile{ID: "00000000000000000000000000000004"},
 		expProps:   nil,
 		expErr: &url.Error{
 			Op:  "Parse",
-			URL: "https://sessionserver.mojang.com/session/minecraft/profile/badProperties",
+			URL: "https://sessionserver.mojang.com/session/minecraft/profile/00000000000000000000000000000004",
 			Err: base64.CorruptInputError(0),
 		},
 	},
-	{
-		profile: &Profile{ID: "tooManyRequests"},
+	{ // Too many requests
+		profile: &Profile{ID: "00000000000000000000000000000005"},
 		transport: statusOverrideTransport{
 			status:    429,
-			transport: http.NewFileTransport(http.Dir("testdata")),
+			transport: testdataTransport,
 		},
-		expProfile: &Profile{ID: "tooManyRequests"},
+		expProfile: &Profile{ID: "00000000000000000000000000000005"},
 		expProps:   nil,
 		expErr:     ErrTooManyRequests,
 	},
 }
 
 func TestProfile_LoadProperties(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testProfileLoadPropertiesInput {
-		client.Transport = tc.transport
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
 		profile := *tc.profile
 
-		props, err := profile.LoadProperties(context.Background(), tc.force)
+		props, err := c.LoadProperties(context.Background(), &profile, tc.force)
 		if !reflect.DeepEqual(&profile, tc.expProfile) || !reflect.DeepEqual(props, tc.expProps) || !reflect.DeepEqual(err, tc.expErr) {
 			t.Errorf(
 				"%#v.LoadProperties(ctx, %t) produced result:\n"+
@@ -476,19 +519,18 @@ func TestProfile_LoadProperties(t *testing.T) {
 }
 
 func TestProfile_LoadPropertiesContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
 
-	profile := Profile{ID: "dummy"}
-	profile.LoadProperties(ctx, true)
+	profile := Profile{ID: "087cc153c3434ff7ac497de1569affa1"}
+	c.LoadProperties(ctx, &profile, true)
 
 	if ct.Context != ctx {
-		t.Error("Profile{ID: \"dummy\"}.LoadProperties(ctx, true) didn't pass context to underlying http.Client")
+		t.Error("Profile{ID: \"087cc153c3434ff7ac497de1569affa1\"}.LoadProperties(ctx, true) didn't pass context to underlying http.Client")
 	}
 }
 
@@ -502,8 +544,19 @@ var testPropertiesSkinReaderInput = [...]struct {
 		props: &Properties{
 			SkinURL: "",
 			Model:   Steve,
+			Textures: &Textures{
+				Timestamp:   msToTime(1495799175553),
+				ProfileID:   "087cc153c3434ff7ac497de1569affa1",
+				ProfileName: "Nergalic",
+				Textures: map[string]Texture{
+					SkinTexture: {
+						URL:  "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+						Hash: "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
+					},
+				},
+			},
 		},
-		transport:  http.NewFileTransport(http.Dir("testdata")),
+		transport:  testdataTransport,
 		expTexture: (func() []byte { b, _ := ioutil.ReadFile("testdata/SkinTemplates/steve.png"); return b })(),
 	},
 	{
@@ -511,7 +564,7 @@ var testPropertiesSkinReaderInput = [...]struct {
 			SkinURL: "",
 			Model:   Alex,
 		},
-		transport:  http.NewFileTransport(http.Dir("testdata")),
+		transport:  testdataTransport,
 		expTexture: (func() []byte { b, _ := ioutil.ReadFile("testdata/SkinTemplates/alex.png"); return b })(),
 	},
 	{
@@ -527,7 +580,7 @@ var testPropertiesSkinReaderInput = [...]struct {
 		props: &Properties{
 			SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 		},
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expTexture: (func() []byte {
 			b, _ := ioutil.ReadFile("testdata/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e")
 			return b
@@ -546,12 +599,12 @@ var testPropertiesSkinReaderInput = [...]struct {
 	},
 	{
 		props: &Properties{
-			SkinURL: alexSkinURL,
+			SkinURL: DefaultAssetsURL + alexSkinPath,
 		},
 		transport: errorTransport{testError},
 		expErr: &url.Error{
 			Op:  "Get",
-			URL: alexSkinURL,
+			URL: DefaultAssetsURL + alexSkinPath,
 			Err: testError,
 		},
 	},
@@ -559,7 +612,7 @@ var testPropertiesSkinReaderInput = [...]struct {
 		props: &Properties{
 			SkinURL: "http://example.com/does/not/exist.png",
 		},
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expErr: &url.Error{
 			Op:  "Get",
 			URL: "http://example.com/does/not/exist.png",
@@ -569,14 +622,13 @@ var testPropertiesSkinReaderInput = [...]struct {
 }
 
 func TestProperties_SkinReader(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testPropertiesSkinReaderInput {
 		var buf bytes.Buffer
-		client.Transport = tc.transport
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
 
-		reader, err := tc.props.SkinReader(context.Background())
+		reader, err := c.SkinReader(context.Background(), tc.props)
 		if reader != nil {
 			buf.ReadFrom(reader)
 		}
@@ -596,18 +648,17 @@ func TestProperties_SkinReader(t *testing.T) {
 }
 
 func TestProperties_SkinReaderContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
 
 	props := Properties{
 		SkinURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 	}
-	props.SkinReader(ctx)
+	c.SkinReader(ctx, &props)
 
 	if ct.Context != ctx {
 		t.Error("Properties{SkinURL: ...}.LoadProperties(ctx) didn't pass context to underlying http.Client")
@@ -632,7 +683,7 @@ var testPropertiesCapeReaderInput = [...]struct {
 		props: &Properties{
 			CapeURL: "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0",
 		},
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expTexture: (func() []byte {
 			b, _ := ioutil.ReadFile("testdata/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0")
 			return b
@@ -651,12 +702,12 @@ var testPropertiesCapeReaderInput = [...]struct {
 	},
 	{
 		props: &Properties{
-			CapeURL: alexSkinURL,
+			CapeURL: DefaultAssetsURL + alexSkinPath,
 		},
 		transport: errorTransport{testError},
 		expErr: &url.Error{
 			Op:  "Get",
-			URL: alexSkinURL,
+			URL: DefaultAssetsURL + alexSkinPath,
 			Err: testError,
 		},
 	},
@@ -664,7 +715,7 @@ var testPropertiesCapeReaderInput = [...]struct {
 		props: &Properties{
 			CapeURL: "http://example.com/does/not/exist.png",
 		},
-		transport: http.NewFileTransport(http.Dir("testdata")),
+		transport: testdataTransport,
 		expErr: &url.Error{
 			Op:  "Get",
 			URL: "http://example.com/does/not/exist.png",
@@ -674,14 +725,13 @@ var testPropertiesCapeReaderInput = [...]struct {
 }
 
 func TestProperties_CapeReader(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	for _, tc := range testPropertiesCapeReaderInput {
 		var buf bytes.Buffer
-		client.Transport = tc.transport
+		c := &Client{HTTPClient: &http.Client{Transport: tc.transport}}
 
-		reader, err := tc.props.CapeReader(context.Background())
+		reader, err := c.CapeReader(context.Background(), tc.props)
 		if reader != nil {
 			buf.ReadFrom(reader)
 		}
@@ -701,18 +751,17 @@ func TestProperties_CapeReader(t *testing.T) {
 }
 
 func TestProperties_CapeReaderContextUsed(t *testing.T) {
-	origTransport := client.Transport
-	defer func() { client.Transport = origTransport }()
+	t.Parallel()
 
 	ctx := context.WithValue(context.Background(), dummy, nil)
 	ct := CtxStoreTransport{}
 
-	client.Transport = &ct
+	c := &Client{HTTPClient: &http.Client{Transport: &ct}}
 
 	props := Properties{
 		CapeURL: "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e",
 	}
-	props.CapeReader(ctx)
+	c.CapeReader(ctx, &props)
 
 	if ct.Context != ctx {
 		t.Error("Properties{CapeURL: ...}.LoadProperties(ctx) didn't pass context to underlying http.Client")
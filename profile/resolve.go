@@ -0,0 +1,112 @@
+package profile
+
+import (
+	"context"
+	"strings"
+)
+
+// Status reports the outcome of resolving a username to a profile.
+type Status byte
+
+const (
+	Found           Status = iota // A profile is associated with the username.
+	NotFound                      // No profile is associated with the username.
+	DemoProfile                   // The username belongs to a demo profile.
+	EmptyUsername                 // The username is empty.
+	InvalidUsername               // The username is malformed.
+)
+
+// String returns a string representation of s.
+//
+//	Found.String()           = "found"
+//	NotFound.String()        = "not found"
+//	DemoProfile.String()     = "demo profile"
+//	EmptyUsername.String()   = "empty username"
+//	InvalidUsername.String() = "invalid username"
+//
+// String returns "???" for statuses not declared by this package.
+func (s Status) String() string {
+	switch s {
+	case Found:
+		return "found"
+	case NotFound:
+		return "not found"
+	case DemoProfile:
+		return "demo profile"
+	case EmptyUsername:
+		return "empty username"
+	case InvalidUsername:
+		return "invalid username"
+	default:
+		return "???"
+	}
+}
+
+// A Resolution is the outcome of resolving a username to a profile.
+type Resolution struct {
+	// Profile is the profile associated with the username if Status is
+	// Found, otherwise nil.
+	Profile *Profile
+	// Status reports whether a profile was found or why not.
+	Status Status
+}
+
+// ResolveMany is a wrapper around DefaultClient.ResolveMany.
+//
+// NB! Only a maximum of LoadManyMaxSize profiles may be fetched at once.
+// If more are attempted loaded in the same operation, an ErrMaxSizeExceeded
+// error is returned.
+func ResolveMany(ctx context.Context, usernames ...string) (rs map[string]Resolution, err error) {
+	return DefaultClient.ResolveMany(ctx, usernames...)
+}
+
+// ResolveMany is like LoadMany, except it reports the outcome for each of the
+// requested usernames. rs maps every username in usernames to either the
+// profile currently associated with it or the reason no profile was returned.
+// Duplicate usernames resolve to the same profile. Empty and malformed
+// usernames, as determined by ValidateUsername and c.LenientUsernames, are
+// not sent to the Mojang servers. rs will be nil if an error occurs. ctx must
+// be non-nil.
+//
+// NB! Only a maximum of LoadManyMaxSize profiles may be fetched at once.
+// If more are attempted loaded in the same operation, an ErrMaxSizeExceeded
+// error is returned.
+func (c *Client) ResolveMany(ctx context.Context, usernames ...string) (rs map[string]Resolution, err error) {
+	if len(usernames) > LoadManyMaxSize {
+		return nil, ErrMaxSizeExceeded{len(usernames)}
+	}
+
+	rs = make(map[string]Resolution, len(usernames))
+	lookup := make([]string, 0, len(usernames))
+	for _, u := range usernames {
+		switch {
+		case u == "":
+			rs[u] = Resolution{Status: EmptyUsername}
+		case c.validateUsername(u) != nil:
+			rs[u] = Resolution{Status: InvalidUsername}
+		default:
+			lookup = append(lookup, u)
+		}
+	}
+
+	ps, demos, err := c.loadMany(ctx, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*Profile, len(ps))
+	for _, p := range ps {
+		found[strings.ToLower(p.Name)] = p
+	}
+	for _, u := range lookup {
+		key := strings.ToLower(u)
+		if p, ok := found[key]; ok {
+			rs[u] = Resolution{Profile: p, Status: Found}
+		} else if demos[key] {
+			rs[u] = Resolution{Status: DemoProfile}
+		} else {
+			rs[u] = Resolution{Status: NotFound}
+		}
+	}
+	return rs, nil
+}
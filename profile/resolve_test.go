@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestResolveMany(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{HTTPClient: &http.Client{Transport: http.NewFileTransport(http.Dir("testdata/LoadMany/success"))}}
+	usernames := []string{"nergalic", "NERGALIC", "AxeLaw", "demo", "doesNotExist", "", "not valid"}
+
+	nergalic := &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}
+	exp := map[string]Resolution{
+		"nergalic":     {Profile: nergalic, Status: Found},
+		"NERGALIC":     {Profile: nergalic, Status: Found},
+		"AxeLaw":       {Profile: &Profile{ID: "cabefc91b5df4c87886a6c604da2e46f", Name: "AxeLaw", NameHistory: emptyHist}, Status: Found},
+		"demo":         {Status: DemoProfile},
+		"doesNotExist": {Status: NotFound},
+		"":             {Status: EmptyUsername},
+		"not valid":    {Status: InvalidUsername},
+	}
+
+	rs, err := c.ResolveMany(context.Background(), usernames...)
+	if !reflect.DeepEqual(rs, exp) || err != nil {
+		t.Errorf(
+			"ResolveMany(ctx, %q)\n"+
+				" was: %v, %s\n"+
+				"want: %v, <nil>",
+			usernames, rs, p(err), exp,
+		)
+	}
+}
+
+func TestResolveManyLenient(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{
+		HTTPClient:       &http.Client{Transport: http.NewFileTransport(http.Dir("testdata/LoadMany/success"))},
+		LenientUsernames: true,
+	}
+	usernames := []string{"nergalic", "legacy-name", "not valid"}
+	exp := map[string]Resolution{
+		"nergalic":    {Profile: &Profile{ID: "087cc153c3434ff7ac497de1569affa1", Name: "Nergalic"}, Status: Found},
+		"legacy-name": {Status: NotFound},
+		"not valid":   {Status: InvalidUsername},
+	}
+	rs, err := c.ResolveMany(context.Background(), usernames...)
+	if !reflect.DeepEqual(rs, exp) || err != nil {
+		t.Errorf(
+			"ResolveMany(ctx, %q) with lenient usernames\n"+
+				" was: %v, %s\n"+
+				"want: %v, <nil>",
+			usernames, rs, p(err), exp,
+		)
+	}
+}
+
+func TestResolveManyErrors(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{HTTPClient: &http.Client{Transport: errorTransport{testError}}}
+
+	rs, err := c.ResolveMany(context.Background(), make([]string, LoadManyMaxSize+1)...)
+	if rs != nil || err != (ErrMaxSizeExceeded{LoadManyMaxSize + 1}) {
+		t.Errorf("ResolveMany(ctx, <%d usernames>) was %v, %s; want nil, ErrMaxSizeExceeded", LoadManyMaxSize+1, rs, p(err))
+	}
+
+	rs, err = c.ResolveMany(context.Background(), "nergalic")
+	if rs != nil || err == nil {
+		t.Errorf("ResolveMany(ctx, \"nergalic\") was %v, %s; want nil, error", rs, p(err))
+	}
+}
+
+var testStatusStringInput = [...]struct {
+	status Status
+	expStr string
+}{
+	{Found, "found"},
+	{NotFound, "not found"},
+	{DemoProfile, "demo profile"},
+	{EmptyUsername, "empty username"},
+	{InvalidUsername, "invalid username"},
+	{Status(99), "???"},
+}
+
+func TestStatus_String(t *testing.T) {
+	for _, tc := range testStatusStringInput {
+		if s := tc.status.String(); s != tc.expStr {
+			t.Errorf("Status(%d).String() was %q; want %q", byte(tc.status), s, tc.expStr)
+		}
+	}
+}
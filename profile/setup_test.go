@@ -6,5 +6,7 @@ import "net/http"
 
 func init() {
 	// Ensure examples normally run as unit tests
-	client.Transport = http.NewFileTransport(http.Dir("testdata"))
+	DefaultClient.HTTPClient = &http.Client{
+		Transport: testdataTransport,
+	}
 }
@@ -0,0 +1,80 @@
+package profile
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+)
+
+// ParsePublicKey parses an RSA public key in PKIX form, either DER or PEM
+// encoded.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	if b, _ := pem.Decode(data); b != nil {
+		data = b.Bytes
+	}
+	key, err := x509.ParsePKIXPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("minecraft/profile: public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// A Property is a profile property as received from the Mojang servers.
+type Property struct {
+	Name      string // Name of the property, e.g. "textures".
+	Value     string // Base64 encoded value of the property.
+	Signature string // Base64 encoded signature of Value, if signed.
+}
+
+// Verify reports whether p.Raw has been signed by Mojang. Verify uses
+// DefaultClient. See Client.Verify.
+func (p *Properties) Verify() error {
+	return DefaultClient.Verify(p)
+}
+
+// Verify checks the signatures of p.Raw against c.PublicKey, or
+// YggdrasilPublicKey if c.PublicKey is nil. It returns nil if every property
+// carries a valid signature.
+//
+// If p holds no signed properties, i.e. if they weren't loaded by a Client
+// with SignedProperties set, ErrUnsignedProperties is returned. If both
+// c.PublicKey and YggdrasilPublicKey are nil, ErrNoPublicKey is returned. If a
+// signature doesn't match, a *SignatureError is returned.
+//
+// Note that only the raw properties are verified. The other fields of p are
+// derived from them when loaded, but aren't protected against later changes.
+func (c *Client) Verify(p *Properties) error {
+	if len(p.Raw) == 0 {
+		return ErrUnsignedProperties
+	}
+	key := c.PublicKey
+	if key == nil {
+		key = YggdrasilPublicKey
+	}
+	if key == nil {
+		return ErrNoPublicKey
+	}
+	for _, prop := range p.Raw {
+		if prop.Signature == "" {
+			return ErrUnsignedProperties
+		}
+		sig, err := base64.StdEncoding.DecodeString(prop.Signature)
+		if err != nil {
+			return &SignatureError{Property: prop.Name}
+		}
+		// Mojang signs the base64 encoded value using SHA1withRSA.
+		h := sha1.Sum([]byte(prop.Value))
+		if rsa.VerifyPKCS1v15(key, crypto.SHA1, h[:], sig) != nil {
+			return &SignatureError{Property: prop.Name}
+		}
+	}
+	return nil
+}
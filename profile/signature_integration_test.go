@@ -0,0 +1,33 @@
+//go:build integration
+// +build integration
+
+package profile
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// Test that YggdrasilPublicKey verifies properties signed by the live Mojang
+// session server.
+func TestYggdrasilPublicKeyLive(t *testing.T) {
+	c := &Client{SignedProperties: true}
+	pr, err := c.LoadWithProperties(context.Background(), "087cc153c3434ff7ac497de1569affa1")
+	if err != nil {
+		t.Fatalf("LoadWithProperties(ctx, \"087cc153c3434ff7ac497de1569affa1\") failed: %s", err)
+	}
+	if err := c.Verify(pr.Properties); err != nil {
+		t.Fatalf("Verify(<properties signed by Mojang>) was %s; want <nil>", err)
+	}
+
+	// Change one byte of the signed value
+	raw := pr.Properties.Raw[0]
+	v := []byte(raw.Value)
+	v[len(v)/2] ^= 1
+	tampered := &Properties{Raw: []Property{{Name: raw.Name, Value: string(v), Signature: raw.Signature}}}
+	exp := &SignatureError{Property: raw.Name}
+	if err := c.Verify(tampered); !reflect.DeepEqual(err, exp) {
+		t.Errorf("Verify(<tampered properties>) was %s; want %s", p(err), exp)
+	}
+}
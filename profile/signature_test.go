@@ -0,0 +1,178 @@
+package profile
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const nergalicTextures = "eyJ0aW1lc3RhbXAiOjE0OTU3OTkxNzU1NTMsInByb2ZpbGVJZCI6IjA4N2NjMTUzYzM0MzRmZjdhYzQ5N2RlMTU2OWFmZmExIiwicHJvZmlsZU5hbWUiOiJOZXJnYWxpYyIsInRleHR1cmVzIjp7IlNLSU4iOnsidXJsIjoiaHR0cDovL3RleHR1cmVzLm1pbmVjcmFmdC5uZXQvdGV4dHVyZS81YjQwZjI1MWY3YzhkYjYwOTQzNDk1ZGI2YmY1NDM1MzEwMmQ2Y2FkMjBkMjI5OWQ1Zjk3M2YzNmI0ZjM2NzdlIn19fQ=="
+
+func TestLoadSignedProperties(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t)
+	sig := sign(t, key, nergalicTextures)
+	st := &signedTransport{
+		body: fmt.Sprintf(`{"id":"087cc153c3434ff7ac497de1569affa1","name":"Nergalic","properties":[{"name":"textures","value":%q,"signature":%q}]}`, nergalicTextures, sig),
+	}
+	c := &Client{
+		HTTPClient:       &http.Client{Transport: st},
+		SignedProperties: true,
+		PublicKey:        &key.PublicKey,
+	}
+
+	pr, err := c.LoadWithProperties(context.Background(), "087cc153c3434ff7ac497de1569affa1")
+	if err != nil {
+		t.Fatalf("LoadWithProperties(ctx, \"087cc153c3434ff7ac497de1569affa1\") failed: %s", err)
+	}
+	if expURL := "https://sessionserver.mojang.com/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1?unsigned=false"; st.url != expURL {
+		t.Errorf("LoadWithProperties requested %q; want %q", st.url, expURL)
+	}
+
+	expRaw := []Property{{Name: "textures", Value: nergalicTextures, Signature: sig}}
+	if !reflect.DeepEqual(pr.Properties.Raw, expRaw) {
+		t.Errorf("Properties.Raw\n was: %#v\nwant: %#v", pr.Properties.Raw, expRaw)
+	}
+	if err := c.Verify(pr.Properties); err != nil {
+		t.Errorf("Verify(p.Properties) was %s; want <nil>", err)
+	}
+
+	pr.Properties.Raw[0].Value = base64.StdEncoding.EncodeToString([]byte(`{"textures":{}}`))
+	if err, exp := c.Verify(pr.Properties), (&SignatureError{Property: "textures"}); !reflect.DeepEqual(err, exp) {
+		t.Errorf("Verify(<tampered properties>) was %s; want %s", p(err), exp)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t)
+	other := testKey(t)
+	signed := &Properties{Raw: []Property{{Name: "textures", Value: nergalicTextures, Signature: sign(t, key, nergalicTextures)}}}
+
+	tests := [...]struct {
+		key    *rsa.PublicKey
+		props  *Properties
+		expErr error
+	}{
+		{key: &key.PublicKey, props: signed, expErr: nil},
+		{key: &other.PublicKey, props: signed, expErr: &SignatureError{Property: "textures"}},
+		{key: nil, props: signed, expErr: &SignatureError{Property: "textures"}}, // Checked against YggdrasilPublicKey
+		{key: &key.PublicKey, props: &Properties{}, expErr: ErrUnsignedProperties},
+		{
+			key:    &key.PublicKey,
+			props:  &Properties{Raw: []Property{{Name: "textures", Value: nergalicTextures}}},
+			expErr: ErrUnsignedProperties,
+		},
+		{
+			key:    &key.PublicKey,
+			props:  &Properties{Raw: []Property{{Name: "textures", Value: nergalicTextures, Signature: "!notBase64"}}},
+			expErr: &SignatureError{Property: "textures"},
+		},
+	}
+	for i, tc := range tests {
+		c := &Client{PublicKey: tc.key}
+		if err := c.Verify(tc.props); !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("tests[%d]: Verify(...) was %s; want %s", i, p(err), p(tc.expErr))
+		}
+	}
+}
+
+// Not parallel as YggdrasilPublicKey is modified.
+func TestVerifyNoPublicKey(t *testing.T) {
+	orig := YggdrasilPublicKey
+	defer func() { YggdrasilPublicKey = orig }()
+	YggdrasilPublicKey = nil
+
+	key := testKey(t)
+	signed := &Properties{Raw: []Property{{Name: "textures", Value: nergalicTextures, Signature: sign(t, key, nergalicTextures)}}}
+	if err := (&Client{}).Verify(signed); err != ErrNoPublicKey {
+		t.Errorf("Verify(...) was %s; want %s", p(err), ErrNoPublicKey)
+	}
+}
+
+func TestYggdrasilPublicKey(t *testing.T) {
+	t.Parallel()
+
+	key := YggdrasilPublicKey
+	if key == nil {
+		t.Fatal("YggdrasilPublicKey was nil; want Mojang's session key")
+	}
+	if n := key.N.BitLen(); n != 4096 {
+		t.Errorf("YggdrasilPublicKey.N was %d bits; want 4096", n)
+	}
+	if key.E != 65537 {
+		t.Errorf("YggdrasilPublicKey.E was %d; want 65537", key.E)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	for _, data := range [][]byte{der, pemData} {
+		pub, err := ParsePublicKey(data)
+		if err != nil || !reflect.DeepEqual(pub, &key.PublicKey) {
+			t.Errorf("ParsePublicKey(%q) was %v, %s; want %v, <nil>", data, pub, p(err), &key.PublicKey)
+		}
+	}
+	if pub, err := ParsePublicKey([]byte("garbage")); pub != nil || err == nil {
+		t.Errorf("ParsePublicKey(\"garbage\") was %v, %s; want nil, error", pub, p(err))
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+func testKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, value string) string {
+	h := sha1.Sum([]byte(value))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+// signedTransport responds to any request with body, storing the URL
+// requested.
+type signedTransport struct {
+	body string
+	url  string
+}
+
+func (st *signedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	st.url = req.URL.String()
+	return &http.Response{
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader(st.body)),
+		Request:    req,
+	}, nil
+}
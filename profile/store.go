@@ -0,0 +1,229 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// A TextureBackend holds texture images for a TextureStore, keyed by their
+// hashes. A TextureBackend must be safe for concurrent use by multiple
+// goroutines.
+type TextureBackend interface {
+	// Get returns the PNG image stored for hash, if any.
+	Get(hash string) (png []byte, ok bool)
+	// Set stores the PNG image png for hash. Failures may be ignored,
+	// causing the texture to be downloaded again later.
+	Set(hash string, png []byte)
+}
+
+// TextureDir is a TextureBackend which stores each texture as a PNG file
+// named by its hash in a directory. Failures to read or write files are
+// treated as textures not being stored.
+type TextureDir struct {
+	dir string
+}
+
+// NewTextureDir returns a TextureDir storing its textures in dir. The
+// directory is created if it doesn't exist.
+func NewTextureDir(dir string) (*TextureDir, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &TextureDir{dir: dir}, nil
+}
+
+// Get returns the PNG image stored for hash, if any.
+func (d *TextureDir) Get(hash string) (png []byte, ok bool) {
+	bs, err := ioutil.ReadFile(d.path(hash))
+	if err != nil {
+		return nil, false
+	}
+	return bs, true
+}
+
+// Set stores the PNG image png for hash.
+func (d *TextureDir) Set(hash string, png []byte) {
+	writeFileAtomic(d.path(hash), png)
+}
+
+// path returns the name of the file holding the texture with the given hash.
+// Hashes are validated by TextureStore to be safe file names.
+func (d *TextureDir) path(hash string) string {
+	return filepath.Join(d.dir, hash+".png")
+}
+
+// A TextureStore downloads textures and keeps them in a TextureBackend.
+// Texture URLs are content-addressed, i.e. the image at a texture URL never
+// changes, so each texture only needs to be downloaded once. Later reads of
+// the same texture are served by the backend.
+//
+// A TextureStore is safe for concurrent use by multiple goroutines. Concurrent
+// reads of a texture which isn't stored yet share a single download, which
+// continues as long as any of them is waiting for it.
+type TextureStore struct {
+	client  *Client
+	backend TextureBackend
+
+	mu       sync.Mutex
+	inflight map[string]*download
+}
+
+// download is a texture download in progress. done is closed when data and
+// err have been set. The download is canceled if all waiters give up on it.
+type download struct {
+	done chan struct{}
+	data []byte
+	err  error
+
+	waiters int // Guarded by TextureStore.mu
+	cancel  context.CancelFunc
+}
+
+// NewTextureStore returns a TextureStore which downloads textures using c and
+// keeps them in backend. If c is nil, DefaultClient is used.
+func NewTextureStore(c *Client, backend TextureBackend) *TextureStore {
+	if c == nil {
+		c = DefaultClient
+	}
+	return &TextureStore{
+		client:   c,
+		backend:  backend,
+		inflight: make(map[string]*download),
+	}
+}
+
+// SkinReader is like Client.SkinReader, except the skin texture is read from
+// s, which downloads it if it hasn't been stored yet.
+func (s *TextureStore) SkinReader(ctx context.Context, p *Properties) (io.ReadCloser, error) {
+	url := p.SkinURL
+	key := textureHash(url)
+	if url == "" {
+		tpl := p.Model.defaultSkinPath()
+		if tpl == "" {
+			return nil, ErrUnknownModel
+		}
+		url = s.client.assetsURL(tpl)
+		key = path.Base(tpl[:len(tpl)-len(path.Ext(tpl))]) // "steve" or "alex"
+	}
+	return s.reader(ctx, key, url)
+}
+
+// CapeReader is like Client.CapeReader, except the cape texture is read from
+// s, which downloads it if it hasn't been stored yet.
+func (s *TextureStore) CapeReader(ctx context.Context, p *Properties) (io.ReadCloser, error) {
+	if p.CapeURL == "" {
+		return nil, ErrNoCape
+	}
+	return s.reader(ctx, textureHash(p.CapeURL), p.CapeURL)
+}
+
+// reader returns a reader of the texture stored under key, downloading it
+// from url if it isn't stored yet.
+func (s *TextureStore) reader(ctx context.Context, key, url string) (io.ReadCloser, error) {
+	data, err := s.load(ctx, key, url)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// load returns the texture stored under key, downloading it from url if it
+// isn't stored yet. Textures which can't be stored under key are downloaded
+// on every call.
+func (s *TextureStore) load(ctx context.Context, key, url string) ([]byte, error) {
+	if !validHash(key) {
+		return s.download(ctx, url)
+	}
+	if data, ok := s.backend.Get(key); ok {
+		return data, nil
+	}
+
+	s.mu.Lock()
+	d, ok := s.inflight[key]
+	if !ok {
+		// The download is shared by all callers, so it mustn't be canceled
+		// along with the ctx of whichever caller started it.
+		dctx, cancel := context.WithCancel(context.Background())
+		d = &download{done: make(chan struct{}), cancel: cancel}
+		s.inflight[key] = d
+		go s.fetch(dctx, key, url, d)
+	}
+	d.waiters++
+	s.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return d.data, d.err
+	case <-ctx.Done():
+		s.mu.Lock()
+		if d.waiters--; d.waiters == 0 {
+			// Nobody wants the texture anymore; later callers start over.
+			d.cancel()
+			if s.inflight[key] == d {
+				delete(s.inflight, key)
+			}
+		}
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// fetch performs the shared download d of the texture at url, storing it
+// under key if successful.
+func (s *TextureStore) fetch(ctx context.Context, key, url string, d *download) {
+	d.data, d.err = s.download(ctx, url)
+	if d.err == nil {
+		s.backend.Set(key, d.data)
+	}
+
+	s.mu.Lock()
+	if s.inflight[key] == d {
+		delete(s.inflight, key)
+	}
+	s.mu.Unlock()
+	d.cancel()
+	close(d.done)
+}
+
+// download fetches the texture at url and checks that it is a PNG image.
+func (s *TextureStore) download(ctx context.Context, url string) ([]byte, error) {
+	r, err := s.client.loadTexture(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = decodePNG(bytes.NewReader(data)); err != nil {
+		if e, ok := err.(*InvalidTextureError); ok {
+			e.URL = url
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// validHash reports whether h is a non-empty string of ASCII letters and
+// digits, making it safe to use as a key in any TextureBackend.
+func validHash(h string) bool {
+	if h == "" {
+		return false
+	}
+	for i := 0; i < len(h); i++ {
+		switch c := h[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+const nergalicSkinURL = "http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"
+
+func TestTextureStore(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "profile-textures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backend, err := NewTextureDir(dir)
+	if err != nil {
+		t.Fatalf("NewTextureDir(%q) failed: %s", dir, err)
+	}
+	ct := &countingTransport{transport: http.NewFileTransport(http.Dir("testdata"))}
+	c := &Client{HTTPClient: &http.Client{Transport: ct}}
+
+	expSkin, _ := ioutil.ReadFile("testdata/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e")
+	expAlex, _ := ioutil.ReadFile("testdata/SkinTemplates/alex.png")
+
+	s := NewTextureStore(c, backend)
+	for i := 0; i < 2; i++ {
+		expectTexture(t, s.SkinReader, &Properties{SkinURL: nergalicSkinURL}, expSkin)
+		expectTexture(t, s.SkinReader, &Properties{Model: Alex}, expAlex)
+	}
+	if n := ct.Count(); n != 2 {
+		t.Errorf("TextureStore downloaded %d textures; want 2", n)
+	}
+
+	// Stored textures are served from the backend by other stores too
+	s = NewTextureStore(c, backend)
+	expectTexture(t, s.SkinReader, &Properties{SkinURL: nergalicSkinURL}, expSkin)
+	if n := ct.Count(); n != 2 {
+		t.Errorf("TextureStore downloaded %d textures; want 2", n)
+	}
+	if _, ok := backend.Get("5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"); !ok {
+		t.Error("TextureDir doesn't hold the downloaded skin texture")
+	}
+}
+
+func TestTextureStoreInvalidPNG(t *testing.T) {
+	t.Parallel()
+
+	backend := newMapBackend()
+	ct := &countingTransport{transport: http.NewFileTransport(http.Dir("testdata"))}
+	c := &Client{HTTPClient: &http.Client{Transport: ct}}
+	s := NewTextureStore(c, backend)
+
+	// A JSON document rather than a PNG image, and a PNG image with a corrupt
+	// IDAT chunk
+	urls := []string{"http://textures.minecraft.net/session/minecraft/profile/087cc153c3434ff7ac497de1569affa1", corruptTextureURL}
+	for j, url := range urls {
+		for i := 1; i <= 2; i++ {
+			r, err := s.CapeReader(context.Background(), &Properties{CapeURL: url})
+			if e, ok := err.(*InvalidTextureError); r != nil || !ok || e.URL != url {
+				t.Errorf("CapeReader(ctx, {CapeURL: %q}) was %v, %s; want nil, *InvalidTextureError with URL", url, r, p(err))
+			}
+			if n := ct.Count(); n != 2*j+i {
+				t.Errorf("TextureStore downloaded %d textures; want %d", n, 2*j+i)
+			}
+		}
+	}
+	if backend.Len() != 0 {
+		t.Errorf("TextureStore stored %d invalid textures; want 0", backend.Len())
+	}
+}
+
+func TestTextureStoreUnsafeHash(t *testing.T) {
+	t.Parallel()
+
+	backend := newMapBackend()
+	ct := &countingTransport{transport: http.NewFileTransport(http.Dir("testdata"))}
+	c := &Client{HTTPClient: &http.Client{Transport: ct}}
+	s := NewTextureStore(c, backend)
+
+	expAlex, _ := ioutil.ReadFile("testdata/SkinTemplates/alex.png")
+	url := "http://textures.minecraft.net/SkinTemplates/alex.png"
+	for i := 1; i <= 2; i++ {
+		expectTexture(t, s.SkinReader, &Properties{SkinURL: url}, expAlex)
+		if n := ct.Count(); n != i {
+			t.Errorf("TextureStore downloaded %d textures; want %d", n, i)
+		}
+	}
+	if backend.Len() != 0 {
+		t.Errorf("TextureStore stored %d textures under unsafe hashes; want 0", backend.Len())
+	}
+}
+
+func TestTextureStoreConcurrent(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	ct := &countingTransport{transport: blockingTransport{release, http.NewFileTransport(http.Dir("testdata"))}}
+	c := &Client{HTTPClient: &http.Client{Transport: ct}}
+	s := NewTextureStore(c, newMapBackend())
+
+	expSkin, _ := ioutil.ReadFile("testdata/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expectTexture(t, s.SkinReader, &Properties{SkinURL: nergalicSkinURL}, expSkin)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := ct.Count(); n != 1 {
+		t.Errorf("TextureStore downloaded %d textures; want 1", n)
+	}
+}
+
+// Test that a reader giving up doesn't fail the download shared with others.
+func TestTextureStoreCanceled(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	ct := &countingTransport{transport: blockingTransport{release, http.NewFileTransport(http.Dir("testdata"))}}
+	c := &Client{HTTPClient: &http.Client{Transport: ct}}
+	s := NewTextureStore(c, newMapBackend())
+	props := &Properties{SkinURL: nergalicSkinURL}
+
+	expSkin, _ := ioutil.ReadFile("testdata/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := s.SkinReader(ctx, props)
+		canceled <- err
+	}()
+	time.Sleep(20 * time.Millisecond) // Let the download start
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		expectTexture(t, s.SkinReader, props, expSkin)
+	}()
+	time.Sleep(20 * time.Millisecond) // Let the second reader wait
+
+	cancel()
+	if err := <-canceled; err != context.Canceled {
+		t.Errorf("SkinReader(<canceled ctx>, ...) failed with %s; want %s", p(err), context.Canceled)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := ct.Count(); n != 1 {
+		t.Errorf("TextureStore downloaded %d textures; want 1", n)
+	}
+}
+
+func TestTextureStoreNoCape(t *testing.T) {
+	t.Parallel()
+
+	s := NewTextureStore(&Client{HTTPClient: &http.Client{Transport: errorTransport{testError}}}, newMapBackend())
+	if r, err := s.CapeReader(context.Background(), &Properties{}); r != nil || err != ErrNoCape {
+		t.Errorf("CapeReader(ctx, {}) was %v, %s; want nil, %s", r, p(err), ErrNoCape)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+type readerFunc func(ctx context.Context, p *Properties) (io.ReadCloser, error)
+
+func expectTexture(t *testing.T, read readerFunc, props *Properties, exp []byte) {
+	r, err := read(context.Background(), props)
+	if err != nil {
+		t.Errorf("Reading texture of %#v failed: %s", props, err)
+		return
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil || !bytes.Equal(data, exp) {
+		t.Errorf("Texture of %#v was %d bytes, %s; want %d bytes, <nil>", props, len(data), p(err), len(exp))
+	}
+}
+
+// mapBackend is an in-memory TextureBackend.
+type mapBackend struct {
+	mu       sync.Mutex
+	textures map[string][]byte
+}
+
+func newMapBackend() *mapBackend {
+	return &mapBackend{textures: make(map[string][]byte)}
+}
+
+func (b *mapBackend) Get(hash string) ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.textures[hash]
+	return data, ok
+}
+
+func (b *mapBackend) Set(hash string, png []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.textures[hash] = png
+}
+
+func (b *mapBackend) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.textures)
+}
+
+// blockingTransport blocks requests until release is closed.
+type blockingTransport struct {
+	release   chan struct{}
+	transport http.RoundTripper
+}
+
+func (bt blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-bt.release
+	return bt.transport.RoundTrip(req)
+}
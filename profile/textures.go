@@ -0,0 +1,203 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"time"
+)
+
+// Texture types known to be used by Mojang.
+const (
+	SkinTexture   = "SKIN"
+	CapeTexture   = "CAPE"
+	ElytraTexture = "ELYTRA"
+)
+
+// Textures is the decoded "textures" property of a profile.
+type Textures struct {
+	// Timestamp is the time instant the property was issued by Mojang.
+	Timestamp time.Time
+	// ProfileID is the ID of the profile the textures belong to.
+	ProfileID ID
+	// ProfileName is the username of the profile at Timestamp.
+	ProfileName string
+	// SignatureRequired reports whether Mojang flagged the property as
+	// requiring a signature.
+	SignatureRequired bool
+	// Textures maps texture types, e.g. SkinTexture, to the textures set for
+	// the profile. Texture types without a texture set are absent.
+	Textures map[string]Texture
+
+	_ struct{} // Ensure Textures is constructed using named parameters.
+}
+
+// Skin returns the profile's skin texture. ok is false if no custom skin has
+// been set.
+func (t *Textures) Skin() (tex Texture, ok bool) {
+	tex, ok = t.Textures[SkinTexture]
+	return
+}
+
+// Cape returns the profile's cape texture. ok is false if the profile has no
+// cape.
+func (t *Textures) Cape() (tex Texture, ok bool) {
+	tex, ok = t.Textures[CapeTexture]
+	return
+}
+
+// Texture describes a texture of a profile.
+type Texture struct {
+	// URL is an URL to the texture image.
+	URL string
+	// Hash identifies the texture image. It is the last path segment of URL.
+	Hash string
+	// Metadata holds additional information about the texture, e.g. "model"
+	// set to "slim" for skins using the Alex model. nil if none.
+	Metadata map[string]string
+
+	_ struct{} // Ensure Texture is constructed using named parameters.
+}
+
+// textureHash extracts the hash of a texture from its URL.
+func textureHash(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	return url[strings.LastIndexByte(url, '/')+1:]
+}
+
+// Skin is a convenience method for retrieving and decoding the skin texture
+// of p. See SkinReader and DecodeSkin. Skin uses DefaultClient.
+func (p *Properties) Skin(ctx context.Context) (image.Image, error) {
+	return DefaultClient.Skin(ctx, p)
+}
+
+// Cape is a convenience method for retrieving and decoding the cape texture
+// of p. See CapeReader and DecodeCape. Cape uses DefaultClient.
+func (p *Properties) Cape(ctx context.Context) (image.Image, error) {
+	return DefaultClient.Cape(ctx, p)
+}
+
+// Skin is like p.Skin(ctx), except c is used to retrieve the skin texture.
+func (c *Client) Skin(ctx context.Context, p *Properties) (image.Image, error) {
+	r, err := c.SkinReader(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTexture(r, skinURL(c, p), DecodeSkin)
+}
+
+// Cape is like p.Cape(ctx), except c is used to retrieve the cape texture.
+func (c *Client) Cape(ctx context.Context, p *Properties) (image.Image, error) {
+	r, err := c.CapeReader(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTexture(r, p.CapeURL, DecodeCape)
+}
+
+// Skin is like Client.Skin, except the skin texture is read from s.
+func (s *TextureStore) Skin(ctx context.Context, p *Properties) (image.Image, error) {
+	r, err := s.SkinReader(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTexture(r, skinURL(s.client, p), DecodeSkin)
+}
+
+// Cape is like Client.Cape, except the cape texture is read from s.
+func (s *TextureStore) Cape(ctx context.Context, p *Properties) (image.Image, error) {
+	r, err := s.CapeReader(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTexture(r, p.CapeURL, DecodeCape)
+}
+
+// skinURL returns the URL the skin texture of p is retrieved from by c.
+func skinURL(c *Client, p *Properties) string {
+	if p.SkinURL != "" {
+		return p.SkinURL
+	}
+	return c.assetsURL(p.Model.defaultSkinPath())
+}
+
+// decodeTexture decodes the texture at url read from r using decode and
+// closes r. url is added to any InvalidTextureError returned by decode.
+func decodeTexture(r io.ReadCloser, url string, decode func(io.Reader) (image.Image, error)) (image.Image, error) {
+	defer r.Close()
+	img, err := decode(r)
+	if e, ok := err.(*InvalidTextureError); ok {
+		e.URL = url
+	}
+	return img, err
+}
+
+// DecodeSkin decodes a skin texture from r and checks its dimensions. Skins
+// are PNG images which either are 64x64 pixels, or 64x32 pixels for legacy
+// skins. HD skins are any multiple of those, e.g. 128x128 or 128x64. If the
+// texture isn't a valid skin, an *InvalidTextureError is returned.
+func DecodeSkin(r io.Reader) (image.Image, error) {
+	img, err := decodePNG(r)
+	if err != nil {
+		return nil, err
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w == 0 || w%64 != 0 || (h != w && h != w/2) {
+		return nil, &InvalidTextureError{
+			Reason: fmt.Sprintf("skin is %dx%d pixels; want 64x64, 64x32 or a multiple thereof", w, h),
+		}
+	}
+	return img, nil
+}
+
+// DecodeCape decodes a cape texture from r and checks its dimensions. Capes
+// are PNG images which are 64x32 pixels. HD capes are any multiple of that,
+// e.g. 128x64. If the texture isn't a valid cape, an *InvalidTextureError is
+// returned.
+func DecodeCape(r io.Reader) (image.Image, error) {
+	img, err := decodePNG(r)
+	if err != nil {
+		return nil, err
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w == 0 || w%64 != 0 || h != w/2 {
+		return nil, &InvalidTextureError{
+			Reason: fmt.Sprintf("cape is %dx%d pixels; want 64x32 or a multiple thereof", w, h),
+		}
+	}
+	return img, nil
+}
+
+// decodePNG decodes a PNG image from r. If r doesn't hold a valid PNG image,
+// an *InvalidTextureError is returned. Errors reading r are returned as is.
+func decodePNG(r io.Reader) (image.Image, error) {
+	er := &errReader{r: r}
+	img, err := png.Decode(er)
+	if err == nil {
+		return img, nil
+	}
+	if er.err != nil {
+		return nil, er.err // Failed to read r
+	}
+	return nil, &InvalidTextureError{Reason: "not a valid PNG image: " + err.Error()}
+}
+
+// errReader records the first error other than io.EOF returned by r, which
+// tells apart failures to read r from malformed data read from it.
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (er *errReader) Read(p []byte) (int, error) {
+	n, err := er.r.Read(p)
+	if err != nil && err != io.EOF && er.err == nil {
+		er.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,171 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var testTextureHashInput = [...]struct {
+	url     string
+	expHash string
+}{
+	{"http://textures.minecraft.net/texture/5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e", "5b40f251f7c8db60943495db6bf54353102d6cad20d2299d5f973f36b4f3677e"},
+	{"http://textures.minecraft.net/texture/abc123?v=2", "abc123"},
+	{"http://textures.minecraft.net/texture/abc123#frag", "abc123"},
+	{"abc123", "abc123"},
+	{"", ""},
+}
+
+func TestTextureHash(t *testing.T) {
+	for _, tc := range testTextureHashInput {
+		if hash := textureHash(tc.url); hash != tc.expHash {
+			t.Errorf("textureHash(%q) was %q; want %q", tc.url, hash, tc.expHash)
+		}
+	}
+}
+
+func TestTextures_SkinCape(t *testing.T) {
+	skin := Texture{URL: "http://textures.minecraft.net/texture/abc123", Hash: "abc123"}
+	ts := &Textures{Textures: map[string]Texture{SkinTexture: skin}}
+
+	if tex, ok := ts.Skin(); !ok || tex.URL != skin.URL {
+		t.Errorf("Skin() was %v, %t; want %v, true", tex, ok, skin)
+	}
+	if tex, ok := ts.Cape(); ok {
+		t.Errorf("Cape() was %v, %t; want {}, false", tex, ok)
+	}
+}
+
+var testDecodeSkinInput = [...]struct {
+	width, height int
+	expErr        error
+}{
+	{width: 64, height: 64},
+	{width: 64, height: 32},
+	{width: 128, height: 128},
+	{width: 192, height: 96},
+	{width: 64, height: 48, expErr: &InvalidTextureError{Reason: "skin is 64x48 pixels; want 64x64, 64x32 or a multiple thereof"}},
+	{width: 32, height: 32, expErr: &InvalidTextureError{Reason: "skin is 32x32 pixels; want 64x64, 64x32 or a multiple thereof"}},
+	{width: 96, height: 96, expErr: &InvalidTextureError{Reason: "skin is 96x96 pixels; want 64x64, 64x32 or a multiple thereof"}},
+}
+
+func TestDecodeSkin(t *testing.T) {
+	for _, tc := range testDecodeSkinInput {
+		img, err := DecodeSkin(bytes.NewReader(encodePNG(tc.width, tc.height)))
+		if !reflect.DeepEqual(err, tc.expErr) || (err == nil) != (img != nil) {
+			t.Errorf("DecodeSkin(<%dx%d PNG>) was %v, %s; want image, %s", tc.width, tc.height, img != nil, p(err), p(tc.expErr))
+		}
+	}
+}
+
+var testDecodeCapeInput = [...]struct {
+	width, height int
+	expErr        error
+}{
+	{width: 64, height: 32},
+	{width: 128, height: 64},
+	{width: 64, height: 64, expErr: &InvalidTextureError{Reason: "cape is 64x64 pixels; want 64x32 or a multiple thereof"}},
+	{width: 22, height: 17, expErr: &InvalidTextureError{Reason: "cape is 22x17 pixels; want 64x32 or a multiple thereof"}},
+}
+
+func TestDecodeCape(t *testing.T) {
+	for _, tc := range testDecodeCapeInput {
+		img, err := DecodeCape(bytes.NewReader(encodePNG(tc.width, tc.height)))
+		if !reflect.DeepEqual(err, tc.expErr) || (err == nil) != (img != nil) {
+			t.Errorf("DecodeCape(<%dx%d PNG>) was %v, %s; want image, %s", tc.width, tc.height, img != nil, p(err), p(tc.expErr))
+		}
+	}
+}
+
+func TestDecodeSkinNotPNG(t *testing.T) {
+	for _, data := range []string{"", "GIF89a", "\x89PNG\r\n\x1a\n"} {
+		img, err := DecodeSkin(strings.NewReader(data))
+		if e, ok := err.(*InvalidTextureError); img != nil || !ok || !strings.HasPrefix(e.Reason, "not a valid PNG image: ") {
+			t.Errorf("DecodeSkin(%q) was %v, %s; want nil, *InvalidTextureError", data, img, p(err))
+		}
+	}
+}
+
+const corruptTextureURL = "http://textures.minecraft.net/texture/0000000000000000000000000000000000000000000000000000000000c0dead"
+
+func TestDecodeCorruptPNG(t *testing.T) {
+	// A cape whose IDAT chunk has a broken zlib header
+	data, err := ioutil.ReadFile("testdata/texture/0000000000000000000000000000000000000000000000000000000000c0dead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &InvalidTextureError{Reason: "not a valid PNG image: zlib: invalid header"}
+	if img, err := DecodeCape(bytes.NewReader(data)); img != nil || !reflect.DeepEqual(err, exp) {
+		t.Errorf("DecodeCape(<corrupt PNG>) was %v, %s; want nil, %s", bounds(img), p(err), exp)
+	}
+
+	// Failures to read the PNG image are not the texture's fault
+	r := iotest.TimeoutReader(bytes.NewReader(encodePNG(64, 64)))
+	if img, err := DecodeSkin(r); img != nil || err != iotest.ErrTimeout {
+		t.Errorf("DecodeSkin(<failing reader>) was %v, %s; want nil, %s", bounds(img), p(err), iotest.ErrTimeout)
+	}
+}
+
+func TestClientSkinCape(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{HTTPClient: &http.Client{Transport: http.NewFileTransport(http.Dir("testdata"))}}
+	ctx := context.Background()
+
+	if img, err := c.Skin(ctx, &Properties{SkinURL: nergalicSkinURL}); err != nil || img.Bounds() != image.Rect(0, 0, 64, 32) {
+		t.Errorf("Skin(ctx, {SkinURL: %q}) was %v, %s; want 64x32 image, <nil>", nergalicSkinURL, bounds(img), p(err))
+	}
+	if img, err := c.Skin(ctx, &Properties{Model: Alex}); err != nil || img.Bounds() != image.Rect(0, 0, 64, 64) {
+		t.Errorf("Skin(ctx, {Model: Alex}) was %v, %s; want 64x64 image, <nil>", bounds(img), p(err))
+	}
+
+	capeURL := "http://textures.minecraft.net/texture/ec80a225b145c812a6ef1ca29af0f3ebf02163874d1a66e53bac99965225e0"
+	if img, err := c.Cape(ctx, &Properties{CapeURL: capeURL}); err != nil || img.Bounds() != image.Rect(0, 0, 64, 32) {
+		t.Errorf("Cape(ctx, {CapeURL: %q}) was %v, %s; want 64x32 image, <nil>", capeURL, bounds(img), p(err))
+	}
+	if img, err := c.Cape(ctx, &Properties{}); img != nil || err != ErrNoCape {
+		t.Errorf("Cape(ctx, {}) was %v, %s; want nil, %s", bounds(img), p(err), ErrNoCape)
+	}
+
+	// A skin texture used as cape has the wrong dimensions
+	img, err := c.Cape(ctx, &Properties{CapeURL: "http://assets.mojang.com/SkinTemplates/steve.png"})
+	exp := &InvalidTextureError{
+		URL:    "http://assets.mojang.com/SkinTemplates/steve.png",
+		Reason: "cape is 64x64 pixels; want 64x32 or a multiple thereof",
+	}
+	if img != nil || !reflect.DeepEqual(err, exp) {
+		t.Errorf("Cape(ctx, {CapeURL: <skin>}) was %v, %s; want nil, %s", bounds(img), p(err), exp)
+	}
+	img, err = c.Skin(ctx, &Properties{SkinURL: corruptTextureURL})
+	exp = &InvalidTextureError{URL: corruptTextureURL, Reason: "not a valid PNG image: zlib: invalid header"}
+	if img != nil || !reflect.DeepEqual(err, exp) {
+		t.Errorf("Skin(ctx, {SkinURL: <corrupt PNG>}) was %v, %s; want nil, %s", bounds(img), p(err), exp)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// encodePNG returns a blank PNG image of the given dimensions.
+func encodePNG(width, height int) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, width, height)))
+	return buf.Bytes()
+}
+
+// bounds returns the bounds of img, or nil if img is nil.
+func bounds(img image.Image) interface{} {
+	if img == nil {
+		return nil
+	}
+	return img.Bounds()
+}
@@ -0,0 +1,41 @@
+package profile
+
+// Username length limits enforced by ValidateUsername.
+const (
+	MinUsernameLength       = 3  // Minimum length of a username.
+	MaxUsernameLength       = 16 // Maximum length of a username.
+	MaxLegacyUsernameLength = 25 // Maximum length of a legacy username.
+)
+
+// ValidateUsername reports whether username is a well-formed username.
+// A username must be 3-16 characters long, and may only consist of letters,
+// digits and underscores.
+//
+// Some profiles were created before these rules were enforced. If lenient is
+// true, such legacy usernames are also accepted: 1-25 characters, each a
+// letter, digit, underscore or dash.
+//
+// If username is malformed, an *InvalidUsernameError is returned.
+func ValidateUsername(username string, lenient bool) error {
+	min, max := MinUsernameLength, MaxUsernameLength
+	if lenient {
+		min, max = 1, MaxLegacyUsernameLength
+	}
+	if len(username) < min || len(username) > max {
+		return &InvalidUsernameError{Username: username}
+	}
+	for i := 0; i < len(username); i++ {
+		switch c := username[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '_':
+		case c == '-' && lenient:
+		default:
+			return &InvalidUsernameError{Username: username}
+		}
+	}
+	return nil
+}
+
+// validateUsername validates username according to c.LenientUsernames.
+func (c *Client) validateUsername(username string) error {
+	return ValidateUsername(username, c.LenientUsernames)
+}
@@ -0,0 +1,39 @@
+package profile
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testValidateUsernameInput = [...]struct {
+	username string
+	lenient  bool
+	expErr   error
+}{
+	{username: "Nergalic"},
+	{username: "abc"},
+	{username: "user_With_16Char"},
+	{username: "", expErr: &InvalidUsernameError{Username: ""}},
+	{username: "ab", expErr: &InvalidUsernameError{Username: "ab"}},
+	{username: "user_With_17Chars", expErr: &InvalidUsernameError{Username: "user_With_17Chars"}},
+	{username: "not valid", expErr: &InvalidUsernameError{Username: "not valid"}},
+	{username: "legacy-name", expErr: &InvalidUsernameError{Username: "legacy-name"}},
+	{username: "a/../b", expErr: &InvalidUsernameError{Username: "a/../b"}},
+	{username: "Nergalíc", expErr: &InvalidUsernameError{Username: "Nergalíc"}},
+	{username: "a", lenient: true},
+	{username: "legacy-name", lenient: true},
+	{username: "a_legacy-name-of-25-chars", lenient: true},
+	{username: "", lenient: true, expErr: &InvalidUsernameError{Username: ""}},
+	{username: "a_legacy-name-of-26-chars_", lenient: true, expErr: &InvalidUsernameError{Username: "a_legacy-name-of-26-chars_"}},
+	{username: "not valid", lenient: true, expErr: &InvalidUsernameError{Username: "not valid"}},
+	{username: "a?b", lenient: true, expErr: &InvalidUsernameError{Username: "a?b"}},
+}
+
+func TestValidateUsername(t *testing.T) {
+	for _, tc := range testValidateUsernameInput {
+		err := ValidateUsername(tc.username, tc.lenient)
+		if !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("ValidateUsername(%q, %t) was %s; want %s", tc.username, tc.lenient, p(err), p(tc.expErr))
+		}
+	}
+}
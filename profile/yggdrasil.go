@@ -0,0 +1,68 @@
+package profile
+
+import "crypto/rsa"
+
+// YggdrasilPublicKey is the public key which property signatures are verified
+// against by clients without a PublicKey of their own. It is initialized to
+// Mojang's Yggdrasil session key, which is distributed with Mojang's authlib
+// as yggdrasil_session_pubkey.der.
+var YggdrasilPublicKey *rsa.PublicKey
+
+func init() {
+	key, err := ParsePublicKey(yggdrasilSessionPubKey)
+	if err != nil {
+		panic("minecraft/profile: invalid Yggdrasil session key: " + err.Error())
+	}
+	YggdrasilPublicKey = key
+}
+
+// yggdrasilSessionPubKey is the contents of yggdrasil_session_pubkey.der: a
+// 4096-bit RSA public key in DER encoded PKIX form.
+var yggdrasilSessionPubKey = []byte{
+	0x30, 0x82, 0x02, 0x22, 0x30, 0x0d, 0x06, 0x09, 0x2a, 0x86, 0x48, 0x86,
+	0xf7, 0x0d, 0x01, 0x01, 0x01, 0x05, 0x00, 0x03, 0x82, 0x02, 0x0f, 0x00,
+	0x30, 0x82, 0x02, 0x0a, 0x02, 0x82, 0x02, 0x01, 0x00, 0xca, 0x50, 0x78,
+	0x07, 0xa9, 0xb9, 0x97, 0x3e, 0xe3, 0xc2, 0xb7, 0x05, 0xcf, 0xa1, 0x5d,
+	0xfd, 0xf9, 0xdf, 0x52, 0x17, 0x2f, 0x97, 0x1b, 0x13, 0x4a, 0x7e, 0x64,
+	0x20, 0xaf, 0xf6, 0x68, 0x61, 0xb5, 0x0b, 0x79, 0x0c, 0xcb, 0x85, 0x63,
+	0x87, 0x09, 0x71, 0x17, 0xa0, 0x45, 0x51, 0x29, 0xdf, 0xc6, 0x13, 0x6c,
+	0xc0, 0xf8, 0xe1, 0xdd, 0x98, 0xad, 0x90, 0x1c, 0x44, 0x00, 0x56, 0x36,
+	0xe6, 0xec, 0xe4, 0x24, 0x54, 0x70, 0x1f, 0x05, 0x40, 0xf6, 0x67, 0x70,
+	0x8d, 0xab, 0x21, 0x5c, 0x82, 0xed, 0x47, 0xa3, 0xaa, 0x74, 0xb5, 0xd7,
+	0x15, 0x5c, 0x94, 0x3c, 0x85, 0x11, 0x2c, 0xe4, 0x96, 0xa8, 0x63, 0x41,
+	0xb5, 0xbd, 0x3c, 0x10, 0xde, 0x5f, 0x32, 0x71, 0xbc, 0x88, 0x52, 0xe0,
+	0x10, 0x92, 0x01, 0x33, 0x1b, 0x3d, 0x06, 0xf1, 0x77, 0xb3, 0x7f, 0xc5,
+	0x86, 0xe1, 0xf2, 0x3c, 0xa8, 0x95, 0x4d, 0x99, 0x97, 0x63, 0x45, 0x98,
+	0xe1, 0x9c, 0x98, 0xaa, 0xfa, 0x0a, 0x47, 0xe5, 0xe3, 0x69, 0x34, 0x4f,
+	0x3e, 0xf0, 0x6c, 0x0d, 0xae, 0x16, 0x23, 0x6b, 0xb5, 0xcf, 0x15, 0xcc,
+	0x78, 0x79, 0x68, 0x75, 0x3d, 0xe5, 0x9c, 0x2c, 0xd5, 0xbf, 0x23, 0x98,
+	0x04, 0xb5, 0xcb, 0xe8, 0x22, 0x60, 0x07, 0x2b, 0x8e, 0xc5, 0xc7, 0x09,
+	0xf2, 0x19, 0xd7, 0x7c, 0x5f, 0x72, 0x63, 0x81, 0x74, 0x69, 0xe2, 0xf4,
+	0xf3, 0x42, 0x73, 0xff, 0x7f, 0x84, 0x04, 0xd1, 0xbc, 0x44, 0xee, 0x01,
+	0xca, 0x1b, 0x41, 0x7f, 0x83, 0x72, 0xf0, 0xd4, 0x3a, 0xb2, 0xd5, 0xac,
+	0x8d, 0xf7, 0x94, 0x22, 0xf1, 0xfb, 0x6d, 0x4f, 0xf8, 0xcc, 0x26, 0x1c,
+	0x60, 0xea, 0xb8, 0x5a, 0xb2, 0x27, 0x5c, 0x7a, 0x92, 0xf2, 0xaa, 0xee,
+	0x0e, 0x62, 0x25, 0xec, 0xfe, 0x57, 0x5c, 0x67, 0x1a, 0x6e, 0xec, 0xd0,
+	0xb2, 0xd3, 0xdf, 0xfe, 0x1d, 0x82, 0x44, 0x05, 0x29, 0x3f, 0xf3, 0xe5,
+	0x1d, 0x77, 0x0c, 0x96, 0xf7, 0xb0, 0x8e, 0x61, 0x94, 0xe8, 0xc7, 0xc3,
+	0x2b, 0xe9, 0x62, 0x7d, 0x27, 0x0d, 0x63, 0x08, 0xcf, 0xc5, 0x1a, 0x38,
+	0x9c, 0xa4, 0xc6, 0x15, 0x97, 0xff, 0xd1, 0xaa, 0x87, 0x0a, 0x1f, 0xc4,
+	0x02, 0xc4, 0x32, 0x2c, 0xaa, 0xce, 0x21, 0x84, 0x44, 0xd2, 0x50, 0xc6,
+	0xdd, 0xbc, 0xc1, 0x87, 0xca, 0x20, 0x20, 0xc2, 0xc0, 0xba, 0x0b, 0x00,
+	0x7c, 0x16, 0xfa, 0x33, 0x79, 0xaa, 0x0a, 0xa2, 0x9a, 0x4c, 0xc0, 0x1f,
+	0xfd, 0x50, 0x23, 0x87, 0x91, 0x45, 0x26, 0x78, 0xd2, 0xfb, 0xb6, 0xc4,
+	0xf9, 0xb6, 0x44, 0x57, 0x4a, 0xb0, 0x6b, 0x1d, 0x42, 0x12, 0x83, 0xcf,
+	0x24, 0xbd, 0x5f, 0x21, 0x08, 0xce, 0x4b, 0xcc, 0xe3, 0xd3, 0xce, 0xa0,
+	0x8e, 0x91, 0x91, 0x0a, 0xd4, 0xb2, 0xf7, 0xe6, 0xd3, 0x3d, 0x37, 0xd3,
+	0x47, 0xf4, 0x89, 0xf9, 0x55, 0x01, 0xe4, 0x6d, 0x88, 0x78, 0x22, 0x2d,
+	0x90, 0xd1, 0xe9, 0x25, 0x7d, 0xb2, 0x82, 0x88, 0xc5, 0x67, 0xd9, 0xf9,
+	0x16, 0x78, 0xfb, 0x47, 0xd6, 0x67, 0x38, 0xcf, 0x8b, 0xf2, 0x7c, 0x88,
+	0x8c, 0xfb, 0xb7, 0x69, 0xe3, 0xef, 0xb5, 0xca, 0xd7, 0x97, 0x22, 0xe1,
+	0x1f, 0xf8, 0x6f, 0x39, 0xf2, 0x0e, 0x8c, 0xfc, 0x4e, 0x96, 0xa5, 0xbe,
+	0x3b, 0x94, 0x30, 0x48, 0x53, 0xf8, 0x38, 0x47, 0x32, 0x2c, 0x05, 0x20,
+	0x0d, 0xd5, 0x20, 0xc9, 0x6d, 0xfa, 0x67, 0x99, 0x81, 0xdb, 0xce, 0x6e,
+	0x29, 0x6f, 0xe2, 0x82, 0x1c, 0xd9, 0xb0, 0xe4, 0xde, 0xbd, 0x55, 0x06,
+	0xd9, 0x0c, 0x03, 0x22, 0xd3, 0x9b, 0x21, 0x5e, 0xa2, 0xf1, 0x10, 0xb1,
+	0x15, 0xb3, 0x76, 0xa0, 0x31, 0xf2, 0x3d, 0xa3, 0xb9, 0x8f, 0x5b, 0x53,
+	0x68, 0x13, 0x72, 0x56, 0xdf, 0x02, 0x03, 0x01, 0x00, 0x01,
+}
@@ -0,0 +1,78 @@
+package skin
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+// A Source retrieves skin and cape textures. It is implemented by
+// *profile.Client and *profile.TextureStore.
+type Source interface {
+	Skin(ctx context.Context, p *profile.Properties) (image.Image, error)
+	Cape(ctx context.Context, p *profile.Properties) (image.Image, error)
+}
+
+// Load retrieves the skin texture of p from src and returns it as a Skin
+// applied to p.Model. If p.SkinURL == "", the default skin of p.Model is
+// retrieved instead. If src is nil, profile.DefaultClient is used.
+// ctx must be non-nil.
+func Load(ctx context.Context, src Source, p *profile.Properties) (*Skin, error) {
+	if src == nil {
+		src = profile.DefaultClient
+	}
+	img, err := src.Skin(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return New(img, p.Model)
+}
+
+// Avatar renders the face of s, i.e. the front of its head, as a size x size
+// image, scaled using nearest-neighbour interpolation. If hat is true, the
+// overlay of the head is drawn on top of the face. If size <= 0, the image
+// is empty.
+func Avatar(s *Skin, size int, hat bool) *image.NRGBA {
+	if size < 0 {
+		size = 0
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	drawScaled(dst, dst.Bounds(), s.Face(Head, Front, Base), draw.Src)
+	if hat {
+		drawScaled(dst, dst.Bounds(), s.Face(Head, Front, Overlay), draw.Over)
+	}
+	return dst
+}
+
+// WriteAvatar retrieves the skin of p from src, renders its avatar as
+// described by Avatar, and writes it to w as a PNG image. If src is nil,
+// profile.DefaultClient is used. ctx must be non-nil.
+func WriteAvatar(ctx context.Context, w io.Writer, src Source, p *profile.Properties, size int, hat bool) error {
+	s, err := Load(ctx, src, p)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, Avatar(s, size, hat))
+}
+
+// drawScaled scales src to the size of r using nearest-neighbour
+// interpolation and draws it at r in dst using op.
+func drawScaled(dst draw.Image, r image.Rectangle, src image.Image, op draw.Op) {
+	sr := src.Bounds()
+	if r.Empty() || sr.Empty() {
+		return
+	}
+	tmp := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := 0; y < r.Dy(); y++ {
+		sy := sr.Min.Y + y*sr.Dy()/r.Dy()
+		for x := 0; x < r.Dx(); x++ {
+			sx := sr.Min.X + x*sr.Dx()/r.Dx()
+			tmp.Set(x, y, src.At(sx, sy))
+		}
+	}
+	draw.Draw(dst, r, tmp, image.Point{}, op)
+}
@@ -0,0 +1,125 @@
+package skin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+var (
+	_ Source = (*profile.Client)(nil)
+	_ Source = (*profile.TextureStore)(nil)
+)
+
+func TestAvatar(t *testing.T) {
+	s, _ := New(coordinateSkin(1), profile.Steve) // Upgrading hides the opaque hat
+
+	img := Avatar(s, 16, false)
+	if b := img.Bounds(); b != image.Rect(0, 0, 16, 16) {
+		t.Fatalf("Avatar(s, 16, false) has bounds %v; want %v", b, image.Rect(0, 0, 16, 16))
+	}
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			exp := color.NRGBA{uint8(8 + x/2), uint8(8 + y/2), 1, 0xff}
+			if c := img.NRGBAAt(x, y); c != exp {
+				t.Fatalf("Avatar(s, 16, false).At(%d, %d) was %v; want %v", x, y, c, exp)
+			}
+		}
+	}
+}
+
+func TestAvatarHat(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	face := color.NRGBA{0x10, 0x20, 0x30, 0xff}
+	hat := color.NRGBA{0xff, 0, 0, 0xff}
+	for y := 8; y < 16; y++ {
+		for x := 8; x < 16; x++ {
+			src.SetNRGBA(x, y, face)
+		}
+	}
+	src.SetNRGBA(40, 8, hat) // Top-left pixel of the hat's front
+	s, _ := New(src, profile.Steve)
+
+	for _, tc := range []struct {
+		hat      bool
+		topLeft  color.NRGBA
+		topRight color.NRGBA
+	}{
+		{false, face, face},
+		{true, hat, face},
+	} {
+		img := Avatar(s, 8, tc.hat)
+		if c := img.NRGBAAt(0, 0); c != tc.topLeft {
+			t.Errorf("Avatar(s, 8, %t).At(0, 0) was %v; want %v", tc.hat, c, tc.topLeft)
+		}
+		if c := img.NRGBAAt(7, 0); c != tc.topRight {
+			t.Errorf("Avatar(s, 8, %t).At(7, 0) was %v; want %v", tc.hat, c, tc.topRight)
+		}
+	}
+}
+
+func TestAvatarSize(t *testing.T) {
+	s, _ := New(coordinateSkin(2), profile.Steve)
+	for _, size := range []int{-1, 0, 1, 5, 100} {
+		exp := size
+		if exp < 0 {
+			exp = 0
+		}
+		if b := Avatar(s, size, true).Bounds(); b != image.Rect(0, 0, exp, exp) {
+			t.Errorf("Avatar(<HD skin>, %d, true) has bounds %v; want %v", size, b, image.Rect(0, 0, exp, exp))
+		}
+	}
+}
+
+func TestWriteAvatar(t *testing.T) {
+	src := &fakeSource{img: coordinateSkin(1)}
+	props := &profile.Properties{Model: profile.Alex}
+
+	var buf bytes.Buffer
+	if err := WriteAvatar(context.Background(), &buf, src, props, 32, true); err != nil {
+		t.Fatalf("WriteAvatar(ctx, w, src, props, 32, true) failed: %s", err)
+	}
+	if src.props != props {
+		t.Error("WriteAvatar didn't retrieve the skin of the given properties")
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("WriteAvatar wrote an invalid PNG image: %s", err)
+	}
+	if b := img.Bounds(); b != image.Rect(0, 0, 32, 32) {
+		t.Errorf("WriteAvatar wrote an image with bounds %v; want %v", b, image.Rect(0, 0, 32, 32))
+	}
+
+	testError := errors.New("test error")
+	if err := WriteAvatar(context.Background(), &buf, &fakeSource{err: testError}, props, 32, true); err != testError {
+		t.Errorf("WriteAvatar(...) with failing source returned %v; want %v", err, testError)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// fakeSource is a Source returning img and err for both skins and capes,
+// storing the properties requested.
+type fakeSource struct {
+	img   image.Image
+	err   error
+	props *profile.Properties
+}
+
+func (s *fakeSource) Skin(ctx context.Context, p *profile.Properties) (image.Image, error) {
+	s.props = p
+	return s.img, s.err
+}
+
+func (s *fakeSource) Cape(ctx context.Context, p *profile.Properties) (image.Image, error) {
+	s.props = p
+	return s.img, s.err
+}
@@ -0,0 +1,130 @@
+package skin
+
+import (
+	"context"
+	"image"
+	"image/draw"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+// View selects which side of a player a render shows.
+type View byte
+
+const (
+	FrontView View = iota // The player seen from the front.
+	BackView              // The player seen from behind.
+)
+
+// Dimensions of a body rendered by Body, in pixels of a 64x64 skin.
+const (
+	BodyWidth  = 16
+	BodyHeight = 32
+)
+
+// bodyLayout holds the position of each body part in the front view of a
+// Steve body.
+var bodyLayout = [...]image.Rectangle{
+	Head:     image.Rect(4, 0, 12, 8),
+	Torso:    image.Rect(4, 8, 12, 20),
+	RightArm: image.Rect(0, 8, 4, 20),
+	LeftArm:  image.Rect(12, 8, 16, 20),
+	RightLeg: image.Rect(4, 20, 8, 32),
+	LeftLeg:  image.Rect(8, 20, 12, 32),
+}
+
+// capeLayout holds the position of the cape, which hangs from the shoulders.
+var capeLayout = image.Rect(3, 8, 13, 24)
+
+// Faces of a 64x32 cape texture seen from behind and from the front.
+var (
+	capeOuter = image.Rect(1, 1, 11, 17)
+	capeInner = image.Rect(12, 1, 22, 17)
+)
+
+// Body renders the full body of s seen from view as a flat image of
+// BodyWidth x BodyHeight pixels, each scaled to scale x scale pixels. Arms
+// are as wide as s.Model() dictates. If overlay is true, the overlay layer
+// is drawn on top of the body. If cape is non-nil, it is drawn hanging from
+// the shoulders. cape must be a cape texture as returned by
+// profile.DecodeCape; see also Cape. If scale <= 0, the image is empty.
+func Body(s *Skin, view View, scale int, overlay bool, cape image.Image) *image.NRGBA {
+	if scale < 0 {
+		scale = 0
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, BodyWidth*scale, BodyHeight*scale))
+
+	face, capeFace := Front, capeInner
+	if view == BackView {
+		face, capeFace = Back, capeOuter
+	}
+
+	if cape != nil && view == FrontView {
+		drawCape(dst, cape, capeFace, scale) // Behind the body
+	}
+	for _, part := range Parts {
+		r := scaleRect(partRect(part, s.model, view), scale)
+		drawScaled(dst, r, s.Face(part, face, Base), draw.Over)
+		if overlay {
+			drawScaled(dst, r, s.Face(part, face, Overlay), draw.Over)
+		}
+	}
+	if cape != nil && view == BackView {
+		drawCape(dst, cape, capeFace, scale) // On top of the body's back
+	}
+	return dst
+}
+
+// Cape retrieves and decodes the cape texture of p from src. If p has no
+// cape, both the image and error returned are nil. If src is nil,
+// profile.DefaultClient is used. ctx must be non-nil.
+func Cape(ctx context.Context, src Source, p *profile.Properties) (image.Image, error) {
+	if src == nil {
+		src = profile.DefaultClient
+	}
+	img, err := src.Cape(ctx, p)
+	if err == profile.ErrNoCape {
+		return nil, nil
+	}
+	return img, err
+}
+
+// drawCape draws the given face of the cape texture cape at the cape's
+// position in a render of the given scale.
+func drawCape(dst *image.NRGBA, cape image.Image, face image.Rectangle, scale int) {
+	cs := cape.Bounds().Dx() / 64 // Scale of HD capes
+	if cs == 0 {
+		return
+	}
+	sr := scaleRect(face, cs).Add(cape.Bounds().Min)
+	drawScaled(dst, scaleRect(capeLayout, scale), subImage(cape, sr), draw.Over)
+}
+
+// partRect returns the position of part of a model in a render from view.
+func partRect(part Part, model profile.Model, view View) image.Rectangle {
+	r := bodyLayout[part]
+	if model == profile.Alex { // Arms are 3 pixels wide and hang at the torso
+		switch part {
+		case RightArm:
+			r.Min.X++
+		case LeftArm:
+			r.Max.X--
+		}
+	}
+	if view == BackView { // Mirror
+		r = image.Rect(BodyWidth-r.Max.X, r.Min.Y, BodyWidth-r.Min.X, r.Max.Y)
+	}
+	return r
+}
+
+// subImage returns the part of img within r.
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	dst := image.NewNRGBA(r)
+	draw.Draw(dst, r, img, r.Min, draw.Src)
+	return dst
+}
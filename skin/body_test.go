@@ -0,0 +1,127 @@
+package skin
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+var testBodyInput = [...]struct {
+	model    profile.Model
+	view     View
+	dst, src image.Point // Pixel at dst in render must equal src in skin.
+}{
+	{profile.Steve, FrontView, image.Pt(4, 0), image.Pt(8, 8)},     // Head front
+	{profile.Steve, FrontView, image.Pt(6, 10), image.Pt(22, 22)},  // Torso front
+	{profile.Steve, FrontView, image.Pt(0, 8), image.Pt(44, 20)},   // Right arm front
+	{profile.Steve, FrontView, image.Pt(12, 8), image.Pt(36, 52)},  // Left arm front
+	{profile.Steve, FrontView, image.Pt(4, 20), image.Pt(4, 20)},   // Right leg front
+	{profile.Steve, FrontView, image.Pt(11, 31), image.Pt(23, 63)}, // Left leg front
+	{profile.Steve, BackView, image.Pt(4, 0), image.Pt(24, 8)},     // Head back
+	{profile.Steve, BackView, image.Pt(6, 10), image.Pt(34, 22)},   // Torso back
+	{profile.Steve, BackView, image.Pt(12, 8), image.Pt(52, 20)},   // Right arm back
+	{profile.Steve, BackView, image.Pt(0, 8), image.Pt(44, 52)},    // Left arm back
+	{profile.Alex, FrontView, image.Pt(1, 8), image.Pt(44, 20)},    // Right arm front
+	{profile.Alex, FrontView, image.Pt(14, 8), image.Pt(38, 52)},   // Left arm front
+	{profile.Alex, BackView, image.Pt(12, 8), image.Pt(51, 20)},    // Right arm back
+}
+
+func TestBody(t *testing.T) {
+	for _, tc := range testBodyInput {
+		s, _ := New(coordinateSkin(1), tc.model)
+		img := Body(s, tc.view, 1, false, nil)
+		if b := img.Bounds(); b != image.Rect(0, 0, BodyWidth, BodyHeight) {
+			t.Fatalf("Body(<%s skin>, %d, 1, false, nil) has bounds %v", tc.model, tc.view, b)
+		}
+		if c, exp := img.NRGBAAt(tc.dst.X, tc.dst.Y), s.img.NRGBAAt(tc.src.X, tc.src.Y); c != exp {
+			t.Errorf("Body(<%s skin>, %d, 1, false, nil).At(%d, %d) was %v; want %v", tc.model, tc.view, tc.dst.X, tc.dst.Y, c, exp)
+		}
+	}
+}
+
+func TestBodyAlexArmGap(t *testing.T) {
+	s, _ := New(coordinateSkin(1), profile.Alex)
+	for _, view := range []View{FrontView, BackView} {
+		img := Body(s, view, 1, false, nil)
+		for _, pt := range []image.Point{{0, 8}, {15, 8}} {
+			if c := img.NRGBAAt(pt.X, pt.Y); c.A != 0 {
+				t.Errorf("Body(<Alex skin>, %d, 1, false, nil).At(%d, %d) was %v; want transparent", view, pt.X, pt.Y, c)
+			}
+		}
+	}
+}
+
+func TestBodyScale(t *testing.T) {
+	s, _ := New(coordinateSkin(2), profile.Steve)
+	img := Body(s, FrontView, 3, true, nil)
+	if b := img.Bounds(); b != image.Rect(0, 0, 3*BodyWidth, 3*BodyHeight) {
+		t.Fatalf("Body(<HD skin>, FrontView, 3, true, nil) has bounds %v; want %v", b, image.Rect(0, 0, 3*BodyWidth, 3*BodyHeight))
+	}
+	// Torso front pixel (1, 1) covers 3x3 render pixels and 2x2 skin pixels
+	if c, exp := img.NRGBAAt(5*3, 9*3), s.img.NRGBAAt(21*2, 21*2); c != exp {
+		t.Errorf("Body(<HD skin>, FrontView, 3, true, nil).At(15, 27) was %v; want %v", c, exp)
+	}
+	if b := Body(s, FrontView, -1, true, nil).Bounds(); !b.Empty() {
+		t.Errorf("Body(<HD skin>, FrontView, -1, true, nil) has bounds %v; want empty", b)
+	}
+}
+
+func TestBodyOverlay(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	base := color.NRGBA{0x10, 0x20, 0x30, 0xff}
+	jacket := color.NRGBA{0xff, 0, 0, 0xff}
+	src.SetNRGBA(20, 20, base)   // Torso front
+	src.SetNRGBA(20, 36, jacket) // Torso overlay front
+	s, _ := New(src, profile.Steve)
+
+	if c := Body(s, FrontView, 1, false, nil).NRGBAAt(4, 8); c != base {
+		t.Errorf("Body(s, FrontView, 1, false, nil).At(4, 8) was %v; want %v", c, base)
+	}
+	if c := Body(s, FrontView, 1, true, nil).NRGBAAt(4, 8); c != jacket {
+		t.Errorf("Body(s, FrontView, 1, true, nil).At(4, 8) was %v; want %v", c, jacket)
+	}
+}
+
+func TestBodyCape(t *testing.T) {
+	s, _ := New(coordinateSkin(1), profile.Steve)
+	cape := image.NewNRGBA(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			cape.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 2, 0xff})
+		}
+	}
+
+	back := Body(s, BackView, 1, false, cape)
+	if c, exp := back.NRGBAAt(3, 8), cape.NRGBAAt(1, 1); c != exp {
+		t.Errorf("Body(s, BackView, 1, false, cape).At(3, 8) was %v; want %v", c, exp)
+	}
+	if c, exp := back.NRGBAAt(12, 23), cape.NRGBAAt(10, 16); c != exp {
+		t.Errorf("Body(s, BackView, 1, false, cape).At(12, 23) was %v; want %v", c, exp)
+	}
+
+	front := Body(s, FrontView, 1, false, cape)
+	if c, exp := front.NRGBAAt(6, 10), s.img.NRGBAAt(22, 22); c != exp {
+		t.Errorf("Body(s, FrontView, 1, false, cape).At(6, 10) was %v; want torso %v", c, exp)
+	}
+	if c, exp := front.NRGBAAt(3, 20), cape.NRGBAAt(12, 13); c != exp {
+		t.Errorf("Body(s, FrontView, 1, false, cape).At(3, 20) was %v; want cape %v", c, exp)
+	}
+}
+
+func TestCape(t *testing.T) {
+	cape := image.NewNRGBA(image.Rect(0, 0, 64, 32))
+	if img, err := Cape(context.Background(), &fakeSource{img: cape}, &profile.Properties{}); img != cape || err != nil {
+		t.Errorf("Cape(ctx, src, props) was %v, %v; want cape, <nil>", img, err)
+	}
+	if img, err := Cape(context.Background(), &fakeSource{err: profile.ErrNoCape}, &profile.Properties{}); img != nil || err != nil {
+		t.Errorf("Cape(ctx, <source without cape>, props) was %v, %v; want nil, <nil>", img, err)
+	}
+	testError := errors.New("test error")
+	if img, err := Cape(context.Background(), &fakeSource{err: testError}, &profile.Properties{}); img != nil || err != testError {
+		t.Errorf("Cape(ctx, <failing source>, props) was %v, %v; want nil, %v", img, err, testError)
+	}
+}
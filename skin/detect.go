@@ -0,0 +1,61 @@
+package skin
+
+import (
+	"image"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+// slimColumns lists the regions of the base layer of a 64x64 skin which hold
+// the arms of the Steve model, but are left unused by the narrower arms of
+// the Alex model.
+var slimColumns = [...]image.Rectangle{
+	// Right arm: bottom and back
+	image.Rect(50, 16, 52, 20),
+	image.Rect(54, 20, 56, 32),
+	// Left arm: bottom and back
+	image.Rect(42, 48, 44, 52),
+	image.Rect(46, 52, 48, 64),
+}
+
+// DetectModel infers the player model a skin is made for from its pixels,
+// for use when the model isn't known from a profile's Properties, e.g. for
+// uploaded skins.
+//
+// Skins made for Alex leave the outermost columns of the Steve arm textures
+// unused, which skin editors and the game client keep transparent. The
+// model is Alex if most of these pixels are transparent and Steve otherwise.
+// confidence is the fraction of the pixels in agreement with the model
+// reported, ranging from 0.5 for a tie to 1 if all pixels agree.
+//
+// Legacy skins predate the Alex model and always are reported as Steve with
+// a confidence of 1. If img doesn't have the dimensions of a skin, an
+// *profile.InvalidTextureError is returned.
+func DetectModel(img image.Image) (model profile.Model, confidence float64, err error) {
+	if err = checkDimensions(img); err != nil {
+		return profile.Steve, 0, err
+	}
+	if IsLegacy(img) {
+		return profile.Steve, 1, nil
+	}
+
+	b := img.Bounds()
+	s := b.Dx() / 64
+	var transparent, total int
+	for _, r := range slimColumns {
+		r = scaleRect(r, s).Add(b.Min)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if _, _, _, a := img.At(x, y).RGBA(); a < 0x8000 {
+					transparent++
+				}
+				total++
+			}
+		}
+	}
+
+	if 2*transparent > total {
+		return profile.Alex, float64(transparent) / float64(total), nil
+	}
+	return profile.Steve, float64(total-transparent) / float64(total), nil
+}
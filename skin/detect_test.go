@@ -0,0 +1,57 @@
+package skin
+
+import (
+	"image"
+	"image/draw"
+	"reflect"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+func TestDetectModel(t *testing.T) {
+	for _, scale := range []int{1, 2} {
+		steve := Upgrade(coordinateSkin(scale)).(*image.NRGBA)
+		alex := Upgrade(coordinateSkin(scale)).(*image.NRGBA)
+		for _, r := range slimColumns {
+			draw.Draw(alex, scaleRect(r, scale), image.Transparent, image.Point{}, draw.Src)
+		}
+		// The right arm's back columns are transparent, but they are only
+		// 24 of the 64 pixels checked.
+		mostlySteve := Upgrade(coordinateSkin(scale)).(*image.NRGBA)
+		draw.Draw(mostlySteve, scaleRect(image.Rect(54, 20, 56, 32), scale), image.Transparent, image.Point{}, draw.Src)
+
+		for _, tc := range []struct {
+			name     string
+			img      image.Image
+			expModel profile.Model
+			expConf  float64
+		}{
+			{"legacy", coordinateSkin(scale), profile.Steve, 1},
+			{"steve", steve, profile.Steve, 1},
+			{"alex", alex, profile.Alex, 1},
+			{"mostly steve", mostlySteve, profile.Steve, 40.0 / 64},
+		} {
+			m, c, err := DetectModel(tc.img)
+			if m != tc.expModel || c != tc.expConf || err != nil {
+				t.Errorf("DetectModel(<%s skin of scale %d>) was %s, %v, %v; want %s, %v, <nil>", tc.name, scale, m, c, err, tc.expModel, tc.expConf)
+			}
+		}
+	}
+}
+
+func TestDetectModelOffsetImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(10, 10, 74, 74))
+	draw.Draw(img, img.Bounds(), image.Opaque, image.Point{}, draw.Src)
+	if m, c, err := DetectModel(img); m != profile.Steve || c != 1 || err != nil {
+		t.Errorf("DetectModel(<offset opaque skin>) was %s, %v, %v; want Steve, 1, <nil>", m, c, err)
+	}
+}
+
+func TestDetectModelInvalid(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	exp := &profile.InvalidTextureError{Reason: "skin is 64x48 pixels; want 64x64, 64x32 or a multiple thereof"}
+	if _, _, err := DetectModel(img); !reflect.DeepEqual(err, exp) {
+		t.Errorf("DetectModel(<64x48 image>) returned error %v; want %s", err, exp)
+	}
+}
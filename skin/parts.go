@@ -0,0 +1,255 @@
+package skin
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+// Part identifies a body part of the player model.
+type Part byte
+
+const (
+	Head Part = iota
+	Torso
+	RightArm
+	LeftArm
+	RightLeg
+	LeftLeg
+)
+
+// Parts lists all body parts of the player model.
+var Parts = [...]Part{Head, Torso, RightArm, LeftArm, RightLeg, LeftLeg}
+
+// String returns a string representation of p.
+//
+//	Head.String()     = "head"
+//	Torso.String()    = "torso"
+//	RightArm.String() = "right arm"
+//	LeftArm.String()  = "left arm"
+//	RightLeg.String() = "right leg"
+//	LeftLeg.String()  = "left leg"
+//
+// String returns "???" for parts not declared by this package.
+func (p Part) String() string {
+	switch p {
+	case Head:
+		return "head"
+	case Torso:
+		return "torso"
+	case RightArm:
+		return "right arm"
+	case LeftArm:
+		return "left arm"
+	case RightLeg:
+		return "right leg"
+	case LeftLeg:
+		return "left leg"
+	default:
+		return "???"
+	}
+}
+
+// Face identifies a face of a body part. Body parts are boxes, and Right and
+// Left refer to the sides of the box which are to the player's right and
+// left.
+type Face byte
+
+const (
+	Top Face = iota
+	Bottom
+	Right
+	Front
+	Left
+	Back
+)
+
+// Faces lists all faces of a body part.
+var Faces = [...]Face{Top, Bottom, Right, Front, Left, Back}
+
+// String returns a string representation of f.
+//
+//	Top.String()    = "top"
+//	Bottom.String() = "bottom"
+//	Right.String()  = "right"
+//	Front.String()  = "front"
+//	Left.String()   = "left"
+//	Back.String()   = "back"
+//
+// String returns "???" for faces not declared by this package.
+func (f Face) String() string {
+	switch f {
+	case Top:
+		return "top"
+	case Bottom:
+		return "bottom"
+	case Right:
+		return "right"
+	case Front:
+		return "front"
+	case Left:
+		return "left"
+	case Back:
+		return "back"
+	default:
+		return "???"
+	}
+}
+
+// Layer identifies one of the two layers of a skin.
+type Layer byte
+
+const (
+	Base    Layer = iota // The body itself.
+	Overlay              // Hat, jacket, sleeves and pants worn on top of the body.
+)
+
+// String returns a string representation of l.
+//
+//	Base.String()    = "base"
+//	Overlay.String() = "overlay"
+//
+// String returns "???" for layers not declared by this package.
+func (l Layer) String() string {
+	switch l {
+	case Base:
+		return "base"
+	case Overlay:
+		return "overlay"
+	default:
+		return "???"
+	}
+}
+
+// A Skin is a skin texture in the modern layout along with the player model
+// it is applied to.
+type Skin struct {
+	img   *image.NRGBA
+	model profile.Model
+}
+
+// New returns the Skin of img applied to model. img may have either the
+// modern or the legacy layout; legacy skins are upgraded using Upgrade.
+//
+// If img doesn't have the dimensions of a skin, an *profile.InvalidTextureError
+// is returned. If model isn't declared by the profile package,
+// profile.ErrUnknownModel is returned.
+func New(img image.Image, model profile.Model) (*Skin, error) {
+	if err := checkDimensions(img); err != nil {
+		return nil, err
+	}
+	if model != profile.Steve && model != profile.Alex {
+		return nil, profile.ErrUnknownModel
+	}
+
+	img = Upgrade(img)
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || nrgba.Bounds().Min != (image.Point{}) {
+		b := img.Bounds()
+		nrgba = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+	}
+	return &Skin{img: nrgba, model: model}, nil
+}
+
+// checkDimensions returns an *profile.InvalidTextureError if img doesn't have
+// the dimensions of a skin.
+func checkDimensions(img image.Image) error {
+	b := img.Bounds()
+	if w, h := b.Dx(), b.Dy(); w == 0 || w%64 != 0 || (h != w && h != w/2) {
+		return &profile.InvalidTextureError{
+			Reason: fmt.Sprintf("skin is %dx%d pixels; want 64x64, 64x32 or a multiple thereof", w, h),
+		}
+	}
+	return nil
+}
+
+// Image returns the skin texture of s in the modern layout.
+func (s *Skin) Image() image.Image {
+	return s.img
+}
+
+// Model returns the player model s is applied to.
+func (s *Skin) Model() profile.Model {
+	return s.model
+}
+
+// Scale returns the scale of s, i.e. 1 for 64x64 skins, 2 for 128x128 HD
+// skins and so on.
+func (s *Skin) Scale() int {
+	return s.img.Bounds().Dx() / 64
+}
+
+// Face returns the given face of a body part as a sub-image of s.Image().
+func (s *Skin) Face(part Part, face Face, layer Layer) image.Image {
+	return s.img.SubImage(scaleRect(FaceRect(part, face, layer, s.model), s.Scale()))
+}
+
+// Size returns the width, height and depth of a body part of model in
+// pixels of a 64x64 skin. Arms are 4 pixels wide for Steve, but only 3
+// pixels wide for Alex.
+func Size(part Part, model profile.Model) (w, h, d int) {
+	switch part {
+	case Head:
+		return 8, 8, 8
+	case Torso:
+		return 8, 12, 4
+	case RightArm, LeftArm:
+		if model == profile.Alex {
+			return 3, 12, 4
+		}
+		return 4, 12, 4
+	default: // Legs
+		return 4, 12, 4
+	}
+}
+
+// origins holds the top-left corner of the texture of each body part in the
+// base and overlay layer of a 64x64 skin.
+var origins = [...][2]image.Point{
+	Head:     {{0, 0}, {32, 0}},
+	Torso:    {{16, 16}, {16, 32}},
+	RightArm: {{40, 16}, {40, 32}},
+	LeftArm:  {{32, 48}, {48, 48}},
+	RightLeg: {{0, 16}, {0, 32}},
+	LeftLeg:  {{16, 48}, {0, 48}},
+}
+
+// FaceRect returns the region of a 64x64 skin holding the texture of the
+// given face of a body part of model. The textures of the faces of a body
+// part of width w, height h and depth d are laid out as follows, relative to
+// the top-left corner of the body part's texture:
+//
+//	     d     w     d     w
+//	  +-----+-----+-----+
+//	d |     | Top | Bot |
+//	  +-----+-----+-----+-----+
+//	h |Right|Front|Left |Back |
+//	  +-----+-----+-----+-----+
+//
+// FaceRect panics if part, face or layer isn't declared by this package.
+func FaceRect(part Part, face Face, layer Layer, model profile.Model) image.Rectangle {
+	w, h, d := Size(part, model)
+	o := origins[part][layer]
+
+	var r image.Rectangle
+	switch face {
+	case Top:
+		r = image.Rect(d, 0, d+w, d)
+	case Bottom:
+		r = image.Rect(d+w, 0, d+2*w, d)
+	case Right:
+		r = image.Rect(0, d, d, d+h)
+	case Front:
+		r = image.Rect(d, d, d+w, d+h)
+	case Left:
+		r = image.Rect(d+w, d, 2*d+w, d+h)
+	case Back:
+		r = image.Rect(2*d+w, d, 2*d+2*w, d+h)
+	default:
+		panic("minecraft/skin: invalid face")
+	}
+	return r.Add(o)
+}
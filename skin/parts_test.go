@@ -0,0 +1,145 @@
+package skin
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+var testFaceRectInput = [...]struct {
+	part   Part
+	face   Face
+	layer  Layer
+	model  profile.Model
+	expRct image.Rectangle
+}{
+	{Head, Front, Base, profile.Steve, image.Rect(8, 8, 16, 16)},
+	{Head, Top, Base, profile.Steve, image.Rect(8, 0, 16, 8)},
+	{Head, Bottom, Base, profile.Steve, image.Rect(16, 0, 24, 8)},
+	{Head, Back, Overlay, profile.Steve, image.Rect(56, 8, 64, 16)},
+	{Torso, Front, Base, profile.Steve, image.Rect(20, 20, 28, 32)},
+	{Torso, Right, Overlay, profile.Steve, image.Rect(16, 36, 20, 48)},
+	{RightArm, Front, Base, profile.Steve, image.Rect(44, 20, 48, 32)},
+	{RightArm, Back, Base, profile.Steve, image.Rect(52, 20, 56, 32)},
+	{RightArm, Front, Base, profile.Alex, image.Rect(44, 20, 47, 32)},
+	{RightArm, Left, Base, profile.Alex, image.Rect(47, 20, 51, 32)},
+	{RightArm, Back, Base, profile.Alex, image.Rect(51, 20, 54, 32)},
+	{RightArm, Bottom, Base, profile.Alex, image.Rect(47, 16, 50, 20)},
+	{LeftArm, Front, Base, profile.Steve, image.Rect(36, 52, 40, 64)},
+	{LeftArm, Front, Overlay, profile.Alex, image.Rect(52, 52, 55, 64)},
+	{RightLeg, Front, Base, profile.Alex, image.Rect(4, 20, 8, 32)},
+	{RightLeg, Front, Overlay, profile.Steve, image.Rect(4, 36, 8, 48)},
+	{LeftLeg, Left, Base, profile.Steve, image.Rect(24, 52, 28, 64)},
+	{LeftLeg, Front, Overlay, profile.Steve, image.Rect(4, 52, 8, 64)},
+}
+
+func TestFaceRect(t *testing.T) {
+	for _, tc := range testFaceRectInput {
+		if r := FaceRect(tc.part, tc.face, tc.layer, tc.model); r != tc.expRct {
+			t.Errorf("FaceRect(%s, %s, %s, %s) was %v; want %v", tc.part, tc.face, tc.layer, tc.model, r, tc.expRct)
+		}
+	}
+}
+
+func TestFaceRectWithinSkin(t *testing.T) {
+	skin := image.Rect(0, 0, 64, 64)
+	for _, m := range []profile.Model{profile.Steve, profile.Alex} {
+		for _, p := range Parts {
+			for _, f := range Faces {
+				for _, l := range []Layer{Base, Overlay} {
+					if r := FaceRect(p, f, l, m); r.Empty() || !r.In(skin) {
+						t.Errorf("FaceRect(%s, %s, %s, %s) was %v; want non-empty rectangle within %v", p, f, l, m, r, skin)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(coordinateSkin(2), profile.Alex)
+	if err != nil {
+		t.Fatalf("New(<128x64 image>, Alex) failed: %s", err)
+	}
+	if b := s.Image().Bounds(); b != image.Rect(0, 0, 128, 128) {
+		t.Errorf("New(<128x64 image>, Alex).Image() has bounds %v; want %v", b, image.Rect(0, 0, 128, 128))
+	}
+	if s.Scale() != 2 || s.Model() != profile.Alex {
+		t.Errorf("New(<128x64 image>, Alex) has scale %d and model %s; want 2 and Alex", s.Scale(), s.Model())
+	}
+
+	face := s.Face(RightArm, Front, Base)
+	if b := face.Bounds(); b != image.Rect(88, 40, 94, 64) {
+		t.Errorf("Face(RightArm, Front, Base) has bounds %v; want %v", b, image.Rect(88, 40, 94, 64))
+	}
+	if c, exp := face.At(88, 40), s.Image().At(88, 40); c != exp {
+		t.Errorf("Face(RightArm, Front, Base).At(88, 40) was %v; want %v", c, exp)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	exp := &profile.InvalidTextureError{Reason: "skin is 64x48 pixels; want 64x64, 64x32 or a multiple thereof"}
+	if s, err := New(img, profile.Steve); s != nil || !reflect.DeepEqual(err, exp) {
+		t.Errorf("New(<64x48 image>, Steve) was %v, %v; want nil, %s", s, err, exp)
+	}
+
+	img = image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	if s, err := New(img, profile.Model(9)); s != nil || err != profile.ErrUnknownModel {
+		t.Errorf("New(<64x64 image>, Model(9)) was %v, %v; want nil, %s", s, err, profile.ErrUnknownModel)
+	}
+}
+
+func TestNewOffsetImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(10, 10, 74, 74))
+	s, err := New(img, profile.Steve)
+	if err != nil {
+		t.Fatalf("New(<offset 64x64 image>, Steve) failed: %s", err)
+	}
+	if b := s.Image().Bounds(); b != image.Rect(0, 0, 64, 64) {
+		t.Errorf("New(<offset 64x64 image>, Steve).Image() has bounds %v; want %v", b, image.Rect(0, 0, 64, 64))
+	}
+}
+
+func TestPartString(t *testing.T) {
+	for _, tc := range []struct {
+		part   Part
+		expStr string
+	}{
+		{Head, "head"}, {Torso, "torso"}, {RightArm, "right arm"}, {LeftArm, "left arm"},
+		{RightLeg, "right leg"}, {LeftLeg, "left leg"}, {Part(99), "???"},
+	} {
+		if s := tc.part.String(); s != tc.expStr {
+			t.Errorf("Part(%d).String() was %q; want %q", byte(tc.part), s, tc.expStr)
+		}
+	}
+}
+
+func TestFaceString(t *testing.T) {
+	for _, tc := range []struct {
+		face   Face
+		expStr string
+	}{
+		{Top, "top"}, {Bottom, "bottom"}, {Right, "right"}, {Front, "front"},
+		{Left, "left"}, {Back, "back"}, {Face(99), "???"},
+	} {
+		if s := tc.face.String(); s != tc.expStr {
+			t.Errorf("Face(%d).String() was %q; want %q", byte(tc.face), s, tc.expStr)
+		}
+	}
+}
+
+func TestLayerString(t *testing.T) {
+	for _, tc := range []struct {
+		layer  Layer
+		expStr string
+	}{
+		{Base, "base"}, {Overlay, "overlay"}, {Layer(99), "???"},
+	} {
+		if s := tc.layer.String(); s != tc.expStr {
+			t.Errorf("Layer(%d).String() was %q; want %q", byte(tc.layer), s, tc.expStr)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package skin
+
+import (
+	"image"
+	"math"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+// RenderOptions configures the 3D renders of RenderHead and RenderBody.
+type RenderOptions struct {
+	// Yaw is the angle in degrees the player is turned around its vertical
+	// axis. Positive angles turn the player's right side towards the viewer.
+	Yaw float64
+	// Pitch is the angle in degrees the player is tilted towards the
+	// viewer. Positive angles show the player from above.
+	Pitch float64
+	// Perspective selects a perspective projection instead of an
+	// orthographic projection.
+	Perspective bool
+	// Overlay enables drawing the overlay layer, slightly offset from the
+	// body like the game client does.
+	Overlay bool
+	// NoShading disables shading the faces of the body by their
+	// orientation.
+	NoShading bool
+}
+
+// Isometric is an isometric view of a player's front and right side seen
+// from above, incl. the overlay layer.
+var Isometric = RenderOptions{Yaw: 45, Pitch: 35.264, Overlay: true}
+
+// RenderHead renders the head of s in 3D as configured by opts. The image is
+// size x size pixels large and the head is centered within it. If size <= 0,
+// the image is empty.
+func RenderHead(s *Skin, size int, opts RenderOptions) *image.NRGBA {
+	return render(s, []Part{Head}, size, opts)
+}
+
+// RenderBody renders the full body of s in 3D as configured by opts. The
+// image is size x size pixels large and the body is centered within it. Arms
+// are as wide as s.Model() dictates. If size <= 0, the image is empty.
+func RenderBody(s *Skin, size int, opts RenderOptions) *image.NRGBA {
+	return render(s, Parts[:], size, opts)
+}
+
+// vec3 is a point or vector in model space, measured in pixels of a 64x64
+// skin. The y axis points up, and the player faces the positive z axis with
+// its left side towards the positive x axis.
+type vec3 struct{ x, y, z float64 }
+
+func (v vec3) add(w vec3) vec3      { return vec3{v.x + w.x, v.y + w.y, v.z + w.z} }
+func (v vec3) sub(w vec3) vec3      { return vec3{v.x - w.x, v.y - w.y, v.z - w.z} }
+func (v vec3) dot(w vec3) float64   { return v.x*w.x + v.y*w.y + v.z*w.z }
+func (v vec3) length() float64      { return math.Sqrt(v.dot(v)) }
+func (v vec3) scale(f float64) vec3 { return vec3{v.x * f, v.y * f, v.z * f} }
+func (v vec3) normalize() vec3      { return v.scale(1 / v.length()) }
+func (v vec3) maxComp(w vec3) vec3 {
+	return vec3{math.Max(v.x, w.x), math.Max(v.y, w.y), math.Max(v.z, w.z)}
+}
+func (v vec3) minComp(w vec3) vec3 {
+	return vec3{math.Min(v.x, w.x), math.Min(v.y, w.y), math.Min(v.z, w.z)}
+}
+
+// partOrigins holds the minimum corner of the box of each body part of a
+// Steve model standing at the origin.
+var partOrigins = [...]vec3{
+	Head:     {-4, 24, -4},
+	Torso:    {-4, 12, -2},
+	RightArm: {-8, 12, -2},
+	LeftArm:  {4, 12, -2},
+	RightLeg: {-4, 0, -2},
+	LeftLeg:  {0, 0, -2},
+}
+
+// box is the box of a layer of a body part in model space.
+type box struct {
+	min, max vec3
+	part     Part
+	layer    Layer
+}
+
+// partBox returns the box of the given layer of a body part of model.
+// Overlay boxes are inflated to surround the body like the game client does:
+// the hat by half a pixel, the rest by a quarter.
+func partBox(part Part, layer Layer, model profile.Model) box {
+	w, h, d := Size(part, model)
+	min := partOrigins[part]
+	if part == RightArm && model == profile.Alex {
+		min.x++ // Thinner arms hang at the torso
+	}
+	b := box{min: min, max: min.add(vec3{float64(w), float64(h), float64(d)}), part: part, layer: layer}
+	if layer == Overlay {
+		grow := 0.25
+		if part == Head {
+			grow = 0.5
+		}
+		b.min = b.min.sub(vec3{grow, grow, grow})
+		b.max = b.max.add(vec3{grow, grow, grow})
+	}
+	return b
+}
+
+// face returns the given face of b as the corner p0 at which the top-left of
+// the face's texture is mapped, the vectors u and v spanning the texture's
+// width and height, and the outwards normal n.
+func (b box) face(f Face) (p0, u, v, n vec3) {
+	x0, y0, z0 := b.min.x, b.min.y, b.min.z
+	x1, y1, z1 := b.max.x, b.max.y, b.max.z
+	w, h, d := x1-x0, y1-y0, z1-z0
+	switch f {
+	case Top:
+		return vec3{x0, y1, z0}, vec3{w, 0, 0}, vec3{0, 0, d}, vec3{0, 1, 0}
+	case Bottom:
+		return vec3{x0, y0, z1}, vec3{w, 0, 0}, vec3{0, 0, -d}, vec3{0, -1, 0}
+	case Right:
+		return vec3{x0, y1, z0}, vec3{0, 0, d}, vec3{0, -h, 0}, vec3{-1, 0, 0}
+	case Front:
+		return vec3{x0, y1, z1}, vec3{w, 0, 0}, vec3{0, -h, 0}, vec3{0, 0, 1}
+	case Left:
+		return vec3{x1, y1, z1}, vec3{0, 0, -d}, vec3{0, -h, 0}, vec3{1, 0, 0}
+	default: // Back
+		return vec3{x1, y1, z0}, vec3{-w, 0, 0}, vec3{0, -h, 0}, vec3{0, 0, -1}
+	}
+}
+
+// light is the direction towards the light source in view space: from the
+// upper left, slightly in front of the player.
+var light = vec3{-0.3, 0.8, 0.5}.normalize()
+
+// render renders the given parts of s in 3D.
+func render(s *Skin, parts []Part, size int, opts RenderOptions) *image.NRGBA {
+	if size < 0 {
+		size = 0
+	}
+	r := newRasterizer(size)
+	if size == 0 {
+		return r.img
+	}
+
+	var boxes []box
+	for _, p := range parts {
+		boxes = append(boxes, partBox(p, Base, s.model))
+	}
+	if opts.Overlay { // Drawn after the base layer for correct blending
+		for _, p := range parts {
+			boxes = append(boxes, partBox(p, Overlay, s.model))
+		}
+	}
+	cam := newCamera(boxes, size, opts)
+
+	for _, b := range boxes {
+		for _, f := range Faces {
+			p0, u, v, n := b.face(f)
+			shade := 1.0
+			if !opts.NoShading {
+				shade = 0.6 + 0.4*math.Max(0, cam.rotate(n).dot(light))
+			}
+			tex := s.Face(b.part, f, b.layer).(*image.NRGBA)
+			r.quad(cam, p0, u, v, tex, shade)
+		}
+	}
+	return r.img
+}
+
+// camera transforms points in model space to screen space.
+type camera struct {
+	center             vec3
+	sinYaw, cosYaw     float64
+	sinPitch, cosPitch float64
+	dist               float64 // Distance from the center to the eye; 0 if orthographic.
+	scale              float64 // Screen pixels per model space unit at the center.
+	half               float64 // Half the screen size.
+}
+
+// newCamera returns a camera which fits boxes within a screen of size x size
+// pixels regardless of its rotation.
+func newCamera(boxes []box, size int, opts RenderOptions) *camera {
+	min, max := boxes[0].min, boxes[0].max
+	for _, b := range boxes[1:] {
+		min, max = min.minComp(b.min), max.maxComp(b.max)
+	}
+	center := min.add(max).scale(0.5)
+	radius := max.sub(center).length()
+
+	yaw, pitch := opts.Yaw*math.Pi/180, opts.Pitch*math.Pi/180
+	c := &camera{
+		center:   center,
+		sinYaw:   math.Sin(yaw),
+		cosYaw:   math.Cos(yaw),
+		sinPitch: math.Sin(pitch),
+		cosPitch: math.Cos(pitch),
+		scale:    float64(size) / (2 * radius),
+		half:     float64(size) / 2,
+	}
+	if opts.Perspective {
+		c.dist = 4 * radius
+		c.scale *= (c.dist - radius) / c.dist // Nearest points are enlarged most
+	}
+	return c
+}
+
+// rotate rotates v from model space into view space, in which the viewer
+// looks along the negative z axis.
+func (c *camera) rotate(v vec3) vec3 {
+	x := v.x*c.cosYaw + v.z*c.sinYaw
+	z := -v.x*c.sinYaw + v.z*c.cosYaw
+	y := v.y*c.cosPitch - z*c.sinPitch
+	z = v.y*c.sinPitch + z*c.cosPitch
+	return vec3{x, y, z}
+}
+
+// project transforms the point p from model space into screen space,
+// returning its screen coordinates, its depth in view space (larger is
+// nearer) and the perspective divisor w.
+func (c *camera) project(p vec3) (x, y, z, w float64) {
+	v := c.rotate(p.sub(c.center))
+	w = 1
+	if c.dist > 0 {
+		w = (c.dist - v.z) / c.dist
+	}
+	return c.half + v.x*c.scale/w, c.half - v.y*c.scale/w, v.z, w
+}
+
+// rasterizer draws textured triangles into an image using a depth buffer.
+type rasterizer struct {
+	img   *image.NRGBA
+	depth []float64
+	size  int
+}
+
+func newRasterizer(size int) *rasterizer {
+	r := &rasterizer{
+		img:   image.NewNRGBA(image.Rect(0, 0, size, size)),
+		depth: make([]float64, size*size),
+		size:  size,
+	}
+	for i := range r.depth {
+		r.depth[i] = math.Inf(-1)
+	}
+	return r
+}
+
+// vertex is a projected vertex. Texture coordinates and depth are divided by
+// the perspective divisor w for perspective-correct interpolation.
+type vertex struct {
+	x, y             float64 // Screen coordinates.
+	invW, uw, vw, zw float64
+}
+
+// quad draws the parallelogram p0, p0+u, p0+u+v, p0+v with tex mapped onto
+// it, multiplying its colors by shade.
+func (r *rasterizer) quad(c *camera, p0, u, v vec3, tex *image.NRGBA, shade float64) {
+	vertexAt := func(p vec3, tu, tv float64) vertex {
+		x, y, z, w := c.project(p)
+		return vertex{x, y, 1 / w, tu / w, tv / w, z / w}
+	}
+	a := vertexAt(p0, 0, 0)
+	b := vertexAt(p0.add(u), 1, 0)
+	cc := vertexAt(p0.add(u).add(v), 1, 1)
+	d := vertexAt(p0.add(v), 0, 1)
+	r.triangle(a, b, cc, tex, shade)
+	r.triangle(a, cc, d, tex, shade)
+}
+
+// edge returns twice the signed area of the triangle a, b, (x, y).
+func edge(a, b vertex, x, y float64) float64 {
+	return (b.x-a.x)*(y-a.y) - (b.y-a.y)*(x-a.x)
+}
+
+// triangle draws the triangle a, b, c with tex mapped onto it, multiplying
+// its colors by shade. Pixels are only drawn if nearer than what already has
+// been drawn, and transparent texels are skipped.
+func (r *rasterizer) triangle(a, b, c vertex, tex *image.NRGBA, shade float64) {
+	area := edge(a, b, c.x, c.y)
+	if area == 0 {
+		return // Seen edge-on
+	}
+	x0 := clamp(int(math.Floor(math.Min(a.x, math.Min(b.x, c.x)))), 0, r.size-1)
+	x1 := clamp(int(math.Ceil(math.Max(a.x, math.Max(b.x, c.x)))), 0, r.size-1)
+	y0 := clamp(int(math.Floor(math.Min(a.y, math.Min(b.y, c.y)))), 0, r.size-1)
+	y1 := clamp(int(math.Ceil(math.Max(a.y, math.Max(b.y, c.y)))), 0, r.size-1)
+
+	tr := tex.Bounds()
+	for y := y0; y <= y1; y++ {
+		py := float64(y) + 0.5
+		for x := x0; x <= x1; x++ {
+			px := float64(x) + 0.5
+			wa := edge(b, c, px, py) / area
+			wb := edge(c, a, px, py) / area
+			wc := edge(a, b, px, py) / area
+			if wa < 0 || wb < 0 || wc < 0 {
+				continue
+			}
+
+			invW := wa*a.invW + wb*b.invW + wc*c.invW
+			z := (wa*a.zw + wb*b.zw + wc*c.zw) / invW
+			i := y*r.size + x
+			if z <= r.depth[i] {
+				continue
+			}
+			tu := (wa*a.uw + wb*b.uw + wc*c.uw) / invW
+			tv := (wa*a.vw + wb*b.vw + wc*c.vw) / invW
+			tx := clamp(tr.Min.X+int(tu*float64(tr.Dx())), tr.Min.X, tr.Max.X-1)
+			ty := clamp(tr.Min.Y+int(tv*float64(tr.Dy())), tr.Min.Y, tr.Max.Y-1)
+
+			if r.blend(x, y, tex.Pix[tex.PixOffset(tx, ty):], shade) {
+				r.depth[i] = z
+			}
+		}
+	}
+}
+
+// blend draws the non-premultiplied RGBA color src shaded by shade over the
+// pixel at (x, y). It reports false if src is transparent.
+func (r *rasterizer) blend(x, y int, src []uint8, shade float64) bool {
+	sa := float64(src[3]) / 0xff
+	if sa == 0 {
+		return false
+	}
+	dst := r.img.Pix[r.img.PixOffset(x, y):]
+	da := float64(dst[3]) / 0xff
+	oa := sa + da*(1-sa)
+	for i := 0; i < 3; i++ {
+		sc := float64(src[i]) * shade
+		dc := float64(dst[i])
+		dst[i] = uint8((sc*sa+dc*da*(1-sa))/oa + 0.5)
+	}
+	dst[3] = uint8(oa*0xff + 0.5)
+	return true
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
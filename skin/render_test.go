@@ -0,0 +1,108 @@
+package skin
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/PhilipBorgesen/minecraft/profile"
+)
+
+var testRenderHeadInput = [...]struct {
+	yaw, pitch float64
+	expTexel   image.Point // Texel expected at the center of the render.
+}{
+	{0, 0, image.Pt(12, 12)},   // Front
+	{180, 0, image.Pt(28, 12)}, // Back
+	{90, 0, image.Pt(4, 12)},   // Right
+	{-90, 0, image.Pt(20, 12)}, // Left
+	{0, 90, image.Pt(12, 4)},   // Top
+}
+
+func TestRenderHead(t *testing.T) {
+	s, _ := New(coordinateSkin(1), profile.Steve)
+	for _, tc := range testRenderHeadInput {
+		opts := RenderOptions{Yaw: tc.yaw, Pitch: tc.pitch, NoShading: true}
+		img := RenderHead(s, 64, opts)
+		if b := img.Bounds(); b != image.Rect(0, 0, 64, 64) {
+			t.Fatalf("RenderHead(s, 64, %+v) has bounds %v", opts, b)
+		}
+		if c, exp := img.NRGBAAt(32, 32), s.img.NRGBAAt(tc.expTexel.X, tc.expTexel.Y); c != exp {
+			t.Errorf("RenderHead(s, 64, %+v).At(32, 32) was %v; want %v", opts, c, exp)
+		}
+		if c := img.NRGBAAt(0, 0); c.A != 0 {
+			t.Errorf("RenderHead(s, 64, %+v).At(0, 0) was %v; want transparent", opts, c)
+		}
+	}
+}
+
+func TestRenderHeadOverlay(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	face := color.NRGBA{0x10, 0x20, 0x30, 0xff}
+	hat := color.NRGBA{0xff, 0, 0, 0xff}
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			src.SetNRGBA(x, y, face)
+		}
+	}
+	src.SetNRGBA(44, 12, hat) // Center of the hat's front
+	s, _ := New(src, profile.Steve)
+
+	opts := RenderOptions{NoShading: true}
+	if c := RenderHead(s, 64, opts).NRGBAAt(32, 32); c != face {
+		t.Errorf("RenderHead(s, 64, %+v).At(32, 32) was %v; want %v", opts, c, face)
+	}
+	opts.Overlay = true
+	if c := RenderHead(s, 64, opts).NRGBAAt(32, 32); c != hat {
+		t.Errorf("RenderHead(s, 64, %+v).At(32, 32) was %v; want %v", opts, c, hat)
+	}
+	// The transparent parts of the hat don't hide the face
+	if c := RenderHead(s, 64, opts).NRGBAAt(20, 32); c != face {
+		t.Errorf("RenderHead(s, 64, %+v).At(20, 32) was %v; want %v", opts, c, face)
+	}
+}
+
+func TestRenderShading(t *testing.T) {
+	s, _ := New(coordinateSkin(1), profile.Steve)
+	texel := s.img.NRGBAAt(12, 12)
+	c := RenderHead(s, 64, RenderOptions{}).NRGBAAt(32, 32)
+	if c.A != 0xff || c.R >= texel.R || c.G >= texel.G || c.R == 0 {
+		t.Errorf("RenderHead(s, 64, {}).At(32, 32) was %v; want %v shaded darker", c, texel)
+	}
+}
+
+func TestRenderPerspective(t *testing.T) {
+	s, _ := New(coordinateSkin(1), profile.Steve)
+	opts := RenderOptions{Perspective: true, NoShading: true}
+	img := RenderHead(s, 64, opts)
+	if c, exp := img.NRGBAAt(32, 32), s.img.NRGBAAt(12, 12); c != exp {
+		t.Errorf("RenderHead(s, 64, %+v).At(32, 32) was %v; want %v", opts, c, exp)
+	}
+	// The head is fitted within the render from any angle
+	for _, yaw := range []float64{0, 30, 45, 60} {
+		opts := RenderOptions{Yaw: yaw, Pitch: 35, Perspective: true, Overlay: true}
+		img := RenderHead(s, 64, opts)
+		for i := 0; i < 64; i++ {
+			for _, pt := range []image.Point{{i, 0}, {i, 63}, {0, i}, {63, i}} {
+				if c := img.NRGBAAt(pt.X, pt.Y); c.A != 0 {
+					t.Fatalf("RenderHead(s, 64, %+v).At(%d, %d) was %v; want transparent", opts, pt.X, pt.Y, c)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderBody(t *testing.T) {
+	s, _ := New(coordinateSkin(1), profile.Alex)
+	opts := RenderOptions{NoShading: true}
+	img := RenderBody(s, 64, opts)
+	if c, exp := img.NRGBAAt(32, 32), s.img.NRGBAAt(24, 28); c != exp {
+		t.Errorf("RenderBody(<Alex skin>, 64, %+v).At(32, 32) was %v; want %v", opts, c, exp)
+	}
+	if b := RenderBody(s, 0, Isometric).Bounds(); !b.Empty() {
+		t.Errorf("RenderBody(<Alex skin>, 0, Isometric) has bounds %v; want empty", b)
+	}
+	if b := RenderBody(s, 100, Isometric).Bounds(); b != image.Rect(0, 0, 100, 100) {
+		t.Errorf("RenderBody(<Alex skin>, 100, Isometric) has bounds %v; want %v", b, image.Rect(0, 0, 100, 100))
+	}
+}
@@ -0,0 +1,106 @@
+// Package skin works with the skin textures of Minecraft profiles, as
+// returned by the profile package, e.g. by Properties.Skin.
+//
+// Skins come in two layouts: the modern 64x64 layout, and the legacy 64x32
+// layout used by skins created before Minecraft 1.8. HD skins are multiples
+// of those. Functions of this package accept both layouts unless stated
+// otherwise. Use Upgrade to convert legacy skins to the modern layout.
+package skin
+
+import (
+	"image"
+	"image/draw"
+)
+
+// IsLegacy reports whether img has the legacy 64x32 skin layout, i.e. whether
+// it is twice as wide as it is tall.
+func IsLegacy(img image.Image) bool {
+	b := img.Bounds()
+	return b.Dx() == 2*b.Dy()
+}
+
+// legacyCopies lists the regions of a legacy skin which are mirrored to form
+// the left arm and left leg of the modern layout. Each entry is the source
+// rectangle's x, y, width and height, followed by the offset to its
+// destination, all in 64x64 pixel units. This is how the game client
+// upgrades legacy skins.
+var legacyCopies = [...][6]int{
+	// Leg: top, bottom, right, front, left and back
+	{4, 16, 4, 4, 16, 32},
+	{8, 16, 4, 4, 16, 32},
+	{0, 20, 4, 12, 24, 32},
+	{4, 20, 4, 12, 16, 32},
+	{8, 20, 4, 12, 8, 32},
+	{12, 20, 4, 12, 16, 32},
+	// Arm: top, bottom, right, front, left and back
+	{44, 16, 4, 4, -8, 32},
+	{48, 16, 4, 4, -8, 32},
+	{40, 20, 4, 12, 0, 32},
+	{44, 20, 4, 12, -8, 32},
+	{48, 20, 4, 12, -16, 32},
+	{52, 20, 4, 12, -8, 32},
+}
+
+// Upgrade returns img converted to the modern skin layout. If img already has
+// the modern layout, it is returned unchanged.
+//
+// A legacy skin is upgraded the way the game client does it: Its right arm
+// and leg are mirrored to form the left arm and leg, which legacy skins lack,
+// and the overlays of the body, arms and legs, which also are new to the
+// modern layout, are left transparent. Like the game client, Upgrade also
+// makes the base layer opaque and hides the hat if it is fully opaque, as
+// early skins often filled the unused hat region with a solid color.
+func Upgrade(img image.Image) image.Image {
+	if !IsLegacy(img) {
+		return img
+	}
+	b := img.Bounds()
+	s := b.Dx() / 64 // Scale of HD skins
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dx()))
+	draw.Draw(dst, image.Rect(0, 0, b.Dx(), b.Dy()), img, b.Min, draw.Src)
+
+	for _, c := range legacyCopies {
+		x0, y0, w, h, dx, dy := c[0]*s, c[1]*s, c[2]*s, c[3]*s, c[4]*s, c[5]*s
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				px := dst.NRGBAAt(x0+x, y0+y)
+				dst.SetNRGBA(x0+dx+w-1-x, y0+dy+y, px) // Mirrored horizontally
+			}
+		}
+	}
+
+	setOpaque(dst, image.Rect(0, 0, 32, 16), s)
+	hideIfOpaque(dst, image.Rect(32, 0, 64, 32), s)
+	setOpaque(dst, image.Rect(0, 16, 64, 32), s)
+	setOpaque(dst, image.Rect(16, 48, 48, 64), s)
+	return dst
+}
+
+// setOpaque makes every pixel of r*s in img opaque.
+func setOpaque(img *image.NRGBA, r image.Rectangle, s int) {
+	r = scaleRect(r, s)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Pix[img.PixOffset(x, y)+3] = 0xff
+		}
+	}
+}
+
+// hideIfOpaque makes every pixel of r*s in img transparent, unless r*s
+// already contains transparent pixels.
+func hideIfOpaque(img *image.NRGBA, r image.Rectangle, s int) {
+	r = scaleRect(r, s)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] < 0x80 {
+				return
+			}
+		}
+	}
+	draw.Draw(img, r, image.Transparent, image.Point{}, draw.Src)
+}
+
+// scaleRect returns r scaled by s.
+func scaleRect(r image.Rectangle, s int) image.Rectangle {
+	return image.Rect(r.Min.X*s, r.Min.Y*s, r.Max.X*s, r.Max.Y*s)
+}
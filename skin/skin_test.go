@@ -0,0 +1,111 @@
+package skin
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestIsLegacy(t *testing.T) {
+	for _, tc := range []struct {
+		w, h int
+		exp  bool
+	}{
+		{64, 32, true},
+		{128, 64, true},
+		{64, 64, false},
+		{128, 128, false},
+	} {
+		if legacy := IsLegacy(image.NewNRGBA(image.Rect(0, 0, tc.w, tc.h))); legacy != tc.exp {
+			t.Errorf("IsLegacy(<%dx%d image>) was %t; want %t", tc.w, tc.h, legacy, tc.exp)
+		}
+	}
+}
+
+func TestUpgradeModern(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	if res := Upgrade(img); res != img {
+		t.Error("Upgrade(<64x64 image>) didn't return the image unchanged")
+	}
+}
+
+var testUpgradeInput = [...]struct {
+	scale    int
+	dst, src image.Point // Pixel at dst in result must equal src in input.
+}{
+	{1, image.Pt(0, 0), image.Pt(0, 0)},      // Head is kept
+	{1, image.Pt(20, 20), image.Pt(20, 20)},  // Body is kept
+	{1, image.Pt(20, 48), image.Pt(7, 16)},   // Left leg top
+	{1, image.Pt(20, 52), image.Pt(7, 20)},   // Left leg front
+	{1, image.Pt(16, 52), image.Pt(11, 20)},  // Left leg right side is right leg left side
+	{1, image.Pt(24, 52), image.Pt(3, 20)},   // Left leg left side is right leg right side
+	{1, image.Pt(31, 63), image.Pt(12, 31)},  // Left leg back
+	{1, image.Pt(36, 52), image.Pt(47, 20)},  // Left arm front
+	{1, image.Pt(32, 52), image.Pt(51, 20)},  // Left arm right side is right arm left side
+	{1, image.Pt(47, 63), image.Pt(52, 31)},  // Left arm back
+	{2, image.Pt(40, 104), image.Pt(15, 40)}, // Left leg front of HD skin
+	{2, image.Pt(72, 104), image.Pt(95, 40)}, // Left arm front of HD skin
+}
+
+func TestUpgrade(t *testing.T) {
+	for _, tc := range testUpgradeInput {
+		src := coordinateSkin(tc.scale)
+		res := Upgrade(src)
+		if b := res.Bounds(); b != image.Rect(0, 0, 64*tc.scale, 64*tc.scale) {
+			t.Errorf("Upgrade(<%dx%d image>) has bounds %v", 64*tc.scale, 32*tc.scale, b)
+			continue
+		}
+		if c, exp := color.NRGBAModel.Convert(res.At(tc.dst.X, tc.dst.Y)), src.At(tc.src.X, tc.src.Y); c != exp {
+			t.Errorf("Upgrade(<%dx%d image>).At(%d, %d) was %v; want %v", 64*tc.scale, 32*tc.scale, tc.dst.X, tc.dst.Y, c, exp)
+		}
+	}
+}
+
+func TestUpgradeOverlays(t *testing.T) {
+	res := Upgrade(coordinateSkin(1))
+	for _, pt := range []image.Point{
+		{0, 32}, {63, 47}, // Overlays of right leg, body and right arm
+		{0, 48}, {15, 63}, // Overlay of left leg
+		{48, 48}, {63, 63}, // Overlay of left arm
+	} {
+		if _, _, _, a := res.At(pt.X, pt.Y).RGBA(); a != 0 {
+			t.Errorf("Upgrade(<64x32 image>).At(%d, %d) has alpha %d; want 0", pt.X, pt.Y, a)
+		}
+	}
+}
+
+func TestUpgradeTransparency(t *testing.T) {
+	src := coordinateSkin(1)
+	src.SetNRGBA(10, 10, color.NRGBA{}) // Transparent pixel in head
+
+	res := Upgrade(src)
+	if _, _, _, a := res.At(10, 10).RGBA(); a != 0xffff {
+		t.Errorf("Upgrade(...).At(10, 10) has alpha %d; want opaque", a)
+	}
+	if _, _, _, a := res.At(40, 8).RGBA(); a != 0 {
+		t.Errorf("Upgrade(<skin with opaque hat>).At(40, 8) has alpha %d; want 0", a)
+	}
+
+	src = coordinateSkin(1)
+	src.SetNRGBA(63, 31, color.NRGBA{}) // Transparent pixel in hat
+	res = Upgrade(src)
+	if c, exp := res.At(40, 8), src.At(40, 8); c != exp {
+		t.Errorf("Upgrade(<skin with transparent hat pixel>).At(40, 8) was %v; want %v", c, exp)
+	}
+}
+
+/***************
+*  TEST UTILS  *
+***************/
+
+// coordinateSkin returns an opaque legacy skin of the given scale, in which
+// each pixel's red and green components are its x and y coordinates.
+func coordinateSkin(scale int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64*scale, 32*scale))
+	for y := 0; y < 32*scale; y++ {
+		for x := 0; x < 64*scale; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 1, 0xff})
+		}
+	}
+	return img
+}
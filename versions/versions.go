@@ -21,6 +21,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/PhilipBorgesen/minecraft/internal"
@@ -37,14 +38,19 @@ type Listing struct {
 
 // Load fetches a listing of Minecraft versions from Mojang's servers. ctx must
 // be non-nil. If an error occurs, a zero-value Listing will be returned. Load
-// reports Mojang server communication failures using *url.Error.
+// reports Mojang server communication failures using *url.Error. If the
+// listing received isn't structured as expected, the *url.Error has Op "Parse"
+// and describes the offending JSON value. Requests failing with a 429 Too Many
+// Requests or 5xx server error response are retried up to 3 times with
+// exponential backoff, unless ctx is done first.
 func Load(ctx context.Context) (Listing, error) {
 	var res Listing
-	m, err := internal.FetchJSON(ctx, client, versionsURL)
+	js, err := internal.FetchJSON(ctx, client, versionsURL)
 	if err == nil {
-		err = initialize(&res, m)
+		err = initialize(&res, js)
 		if err != nil {
 			res = Listing{}
+			err = &url.Error{Op: "Parse", URL: versionsURL, Err: err}
 		}
 	}
 	return res, err
@@ -105,7 +111,7 @@ func (t Type) String() string {
 // For the same reasons, do not use == with Version values; use Equal instead.
 type Version struct {
 	ID       string    // Version identifier, e.g. "1.8.1".
-	Released time.Time // When the version was released.
+	Released time.Time // When the version was released; zero if unknown.
 	Type     Type      // Type of release, e.g. ordinary release or development snapshot.
 }
 
@@ -124,43 +130,70 @@ func (v Version) String() string {
 
 var client = &http.Client{}
 
-func initialize(l *Listing, j interface{}) (err error) {
-	defer func() { // If JSON data isn't structured as expected
-		if r := recover(); r != nil {
-			err = &url.Error{
-				Op:  "Parse",
-				URL: versionsURL,
-				Err: internal.ErrUnknownFormat,
-			}
-		}
-	}()
+// listingJSON is a versions listing as served by Mojang.
+type listingJSON struct {
+	Latest struct {
+		Snapshot string `json:"snapshot"`
+		Release  string `json:"release"`
+	} `json:"latest"`
+	Versions []versionJSON `json:"versions"`
+}
 
-	m := j.(map[string]interface{})
+type versionJSON struct {
+	ID          string `json:"id"`
+	Type        Type   `json:"type"`
+	ReleaseTime string `json:"releaseTime"`
+}
 
-	l.Versions = make(map[string]Version)
+// initialize fills out l from the JSON document js. If js isn't structured
+// as expected, a *internal.FormatError is returned.
+func initialize(l *Listing, js []byte) error {
+	var lj listingJSON
+	if err := internal.Decode(js, &lj); err != nil {
+		return err
+	}
 
-	latest := m["latest"].(map[string]interface{})
-	l.Latest.Snapshot = latest["snapshot"].(string)
-	l.Latest.Release = latest["release"].(string)
+	switch {
+	case lj.Latest.Snapshot == "":
+		return internal.Missing("latest.snapshot", "string")
+	case lj.Latest.Release == "":
+		return internal.Missing("latest.release", "string")
+	case lj.Versions == nil:
+		return internal.Missing("versions", "array")
+	}
+	l.Latest.Snapshot = lj.Latest.Snapshot
+	l.Latest.Release = lj.Latest.Release
 
-	vm := l.Versions
-	for _, v := range m["versions"].([]interface{}) {
+	l.Versions = make(map[string]Version, len(lj.Versions))
+	for i := range lj.Versions {
 		var vers Version
-		buildVersion(v.(map[string]interface{}), &vers)
-		vm[vers.ID] = vers
+		if err := buildVersion(&lj.Versions[i], &vers); err != nil {
+			return internal.AtPath(err, "versions."+strconv.Itoa(i))
+		}
+		l.Versions[vers.ID] = vers
 	}
 
 	return nil
 }
 
-func buildVersion(m map[string]interface{}, v *Version) {
-	v.ID = m["id"].(string)
-	v.Released = parseTime(m["releaseTime"].(string))
-	v.Type = Type(m["type"].(string))
+func buildVersion(js *versionJSON, v *Version) error {
+	switch {
+	case js.ID == "":
+		return internal.Missing("id", "string")
+	case js.Type == "":
+		return internal.Missing("type", "string")
+	case js.ReleaseTime == "":
+		return internal.Missing("releaseTime", "string")
+	}
+	v.ID = js.ID
+	v.Released = parseTime(js.ReleaseTime)
+	v.Type = js.Type
+	return nil
 }
 
+// parseTime parses an RFC 3339 timestamp. A malformed timestamp only spoils
+// the release time of a single version, so it is parsed as the zero time.
 func parseTime(t string) time.Time {
-	const timeFormat = "2006-01-02T15:04:05-07:00"
-	tm, _ := time.Parse(timeFormat, t)
+	tm, _ := time.Parse(time.RFC3339, t)
 	return tm
 }
@@ -1,9 +1,11 @@
 package versions
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -138,7 +140,12 @@ var testLoadErrorsInput = [...]struct {
 	{
 		transport: http.NewFileTransport(http.Dir("testdata/malstructured")),
 		op:        "Parse",
-		errStr:    internal.ErrUnknownFormat.Error(),
+		errStr:    internal.Missing("latest.snapshot", "string").Error(),
+	},
+	{
+		transport: bodyTransport(`{"latest":{"snapshot":"a","release":"b"},"versions":[{"id":"a","type":"snapshot","releaseTime":"2017-02-08T13:16:29+00:00"},{"id":7}]}`),
+		op:        "Parse",
+		errStr:    (&internal.FormatError{Path: "versions.1.id", Expected: "string", Actual: "number"}).Error(),
 	},
 }
 
@@ -166,6 +173,51 @@ func TestLoadError(t *testing.T) {
 	}
 }
 
+var testReleaseTimeInput = [...]struct {
+	releaseTime string
+	expReleased time.Time
+}{
+	{"2017-02-08T13:16:29+00:00", time.Date(2017, 02, 8, 13, 16, 29, 00, time.UTC)},
+	{"2017-02-08T13:16:29Z", time.Date(2017, 02, 8, 13, 16, 29, 00, time.UTC)},
+	{"2017-02-08T14:16:29.5+01:00", time.Date(2017, 02, 8, 13, 16, 29, 500000000, time.UTC)},
+	{"yesterday", time.Time{}}, // Unknown
+}
+
+func TestLoadReleaseTime(t *testing.T) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	for _, tc := range testReleaseTimeInput {
+		client.Transport = bodyTransport(`{"latest":{"snapshot":"a","release":"a"},"versions":[{"id":"a","type":"release","releaseTime":"` + tc.releaseTime + `"}]}`)
+		vs, err := Load(context.Background())
+		if err != nil {
+			t.Errorf("Load(ctx) with releaseTime %q failed: %s", tc.releaseTime, err)
+		} else if r := vs.Versions["a"].Released; !r.Equal(tc.expReleased) {
+			t.Errorf("Load(ctx) with releaseTime %q was released %s; want %s", tc.releaseTime, r, tc.expReleased)
+		}
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	origTransport := client.Transport
+	defer func() { client.Transport = origTransport }()
+
+	manifest, err := ioutil.ReadFile("testdata/cached/mc/game/version_manifest.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	client.Transport = bodyTransport(manifest)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Load(ctx); err != nil {
+			b.Fatalf("Load(ctx) failed: %s", err)
+		}
+	}
+}
+
 func TestLatestReleasePanic(t *testing.T) {
 	var l Listing
 	l.Versions = make(map[string]Version)
@@ -256,3 +308,15 @@ func (ct *CtxStoreTransport) RoundTrip(req *http.Request) (*http.Response, error
 	ct.Context = req.Context()
 	return nil, errors.New("RoundTrip was called")
 }
+
+// bodyTransport responds to every request with 200 OK and itself as body.
+type bodyTransport []byte
+
+func (bt bodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(bt)),
+		Request:    req,
+	}, nil
+}